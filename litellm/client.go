@@ -4,12 +4,24 @@ import (
 	"bytes"
 	"crypto/tls"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io/ioutil"
 	"log"
 	"net/http"
+	"net/url"
 )
 
+// APIError is returned when the LiteLLM API responds with a non-200 status code.
+type APIError struct {
+	StatusCode int
+	Body       string
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("API request failed with status code %d: %s", e.StatusCode, e.Body)
+}
+
 type Client struct {
 	APIBase    string
 	APIKey     string
@@ -51,7 +63,16 @@ func (c *Client) DeleteTeam(teamID string) error {
 
 // Key-related methods
 func (c *Client) CreateKey(key *Key) (*Key, error) {
-	resp, err := c.sendRequest("POST", "/key/generate", key)
+	path := "/key/generate"
+	if key.ServiceAccountID != "" {
+		// Service account keys are owned by a team instead of a user
+		path = "/key/service-account/generate"
+		body := *key
+		body.Metadata = withServiceAccountID(key.Metadata, key.ServiceAccountID)
+		key = &body
+	}
+
+	resp, err := c.sendRequest("POST", path, key)
 	if err != nil {
 		return nil, err
 	}
@@ -60,34 +81,106 @@ func (c *Client) CreateKey(key *Key) (*Key, error) {
 }
 
 func (c *Client) GetKey(keyID string) (*Key, error) {
-	resp, err := c.sendRequest("GET", fmt.Sprintf("/key/info?key=%s", keyID), nil)
+	resp, err := c.sendRequest("GET", fmt.Sprintf("/key/info?key=%s", url.QueryEscape(keyID)), nil)
 	if err != nil {
 		return nil, err
 	}
 
+	// /key/info nests the key details under "info"
+	if info, ok := resp["info"].(map[string]interface{}); ok {
+		return c.parseKeyResponse(info)
+	}
+
 	return c.parseKeyResponse(resp)
 }
 
+// errKeyAliasNotFound is returned by GetKeyByAlias when no key has the alias.
+var errKeyAliasNotFound = errors.New("no key found")
+
+// GetKeyByAlias looks up a key by its key_alias through the key list API.
+func (c *Client) GetKeyByAlias(alias string) (*Key, error) {
+	resp, err := c.sendRequest("GET", fmt.Sprintf("/key/list?key_alias=%s&return_full_object=true", url.QueryEscape(alias)), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	keys, _ := resp["keys"].([]interface{})
+
+	var matches []*Key
+	for _, k := range keys {
+		data, ok := k.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		key, err := c.parseKeyResponse(data)
+		if err != nil {
+			return nil, err
+		}
+		// Older proxies ignore the key_alias filter, so match on it here too
+		if key.KeyAlias == alias {
+			matches = append(matches, key)
+		}
+	}
+
+	switch len(matches) {
+	case 0:
+		return nil, fmt.Errorf("%w with key_alias %q", errKeyAliasNotFound, alias)
+	case 1:
+		return matches[0], nil
+	default:
+		return nil, fmt.Errorf("found %d keys with key_alias %q", len(matches), alias)
+	}
+}
+
 func (c *Client) UpdateKey(key *Key) (*Key, error) {
-	// Create a new map with only the fields that can be updated
+	// Create a new map with only the fields that can be updated. user_id can't
+	// be changed through /key/update and is ForceNew on the resource, and
+	// send_invite_email only applies on creation. Unset limits are sent as
+	// null so that the proxy clears them.
 	updateData := map[string]interface{}{
-		"key":                   key.Key,
-		"models":                key.Models,
-		"max_budget":            key.MaxBudget,
-		"team_id":               key.TeamID,
-		"max_parallel_requests": key.MaxParallelRequests,
-		"metadata":              key.Metadata,
-		"tpm_limit":             key.TPMLimit,
-		"rpm_limit":             key.RPMLimit,
-		"budget_duration":       key.BudgetDuration,
-		"key_alias":             key.KeyAlias,
-		"aliases":               key.Aliases,
-		"permissions":           key.Permissions,
-		"model_max_budget":      key.ModelMaxBudget,
-		"model_rpm_limit":       key.ModelRPMLimit,
-		"model_tpm_limit":       key.ModelTPMLimit,
-		"guardrails":            key.Guardrails,
-		"blocked":               key.Blocked,
+		"key":                    key.Key,
+		"models":                 key.Models,
+		"max_budget":             key.MaxBudget,
+		"soft_budget":            key.SoftBudget,
+		"team_id":                nullIfEmpty(key.TeamID),
+		"max_parallel_requests":  key.MaxParallelRequests,
+		"metadata":               withServiceAccountID(key.Metadata, key.ServiceAccountID),
+		"tpm_limit":              key.TPMLimit,
+		"rpm_limit":              key.RPMLimit,
+		"budget_duration":        nullIfEmpty(key.BudgetDuration),
+		"allowed_cache_controls": key.AllowedCacheControls,
+		"key_alias":              nullIfEmpty(key.KeyAlias),
+		"aliases":                key.Aliases,
+		"config":                 key.Config,
+		"permissions":            key.Permissions,
+		"model_max_budget":       key.ModelMaxBudget,
+		"model_rpm_limit":        key.ModelRPMLimit,
+		"model_tpm_limit":        key.ModelTPMLimit,
+		"guardrails":             key.Guardrails,
+		"blocked":                key.Blocked,
+		"tags":                   key.Tags,
+		"allowed_routes":         key.AllowedRoutes,
+		"enforced_params":        key.EnforcedParams,
+	}
+
+	// duration restarts the key's expiry, so callers only set it when it changed
+	if key.Duration != "" {
+		updateData["duration"] = key.Duration
+	}
+
+	if key.TempBudgetIncrease != nil {
+		updateData["temp_budget_increase"] = *key.TempBudgetIncrease
+		updateData["temp_budget_expiry"] = key.TempBudgetExpiry
+	} else if key.ClearTempBudget {
+		updateData["temp_budget_increase"] = nil
+		updateData["temp_budget_expiry"] = nil
+	}
+
+	// auto_rotate and rotation_interval are only understood by newer proxies,
+	// so callers only ask for them when they changed
+	if key.UpdateRotation {
+		updateData["auto_rotate"] = key.AutoRotate
+		updateData["rotation_interval"] = nullIfEmpty(key.RotationInterval)
 	}
 
 	resp, err := c.sendRequest("POST", "/key/update", updateData)
@@ -98,6 +191,17 @@ func (c *Client) UpdateKey(key *Key) (*Key, error) {
 	return c.parseKeyResponse(resp)
 }
 
+// RegenerateKey rotates a key in place. The key keeps its settings and spend,
+// but gets a new secret and hashed token.
+func (c *Client) RegenerateKey(keyID string) (*Key, error) {
+	resp, err := c.sendRequest("POST", fmt.Sprintf("/key/%s/regenerate", url.PathEscape(keyID)), map[string]interface{}{})
+	if err != nil {
+		return nil, err
+	}
+
+	return c.parseKeyResponse(resp)
+}
+
 func (c *Client) DeleteKey(keyID string) error {
 	payload := map[string]interface{}{
 		"keys": []string{keyID},
@@ -106,6 +210,50 @@ func (c *Client) DeleteKey(keyID string) error {
 	return err
 }
 
+// ListTeamKeys returns all keys owned by a team, following the key list API's
+// pagination.
+func (c *Client) ListTeamKeys(teamID string) ([]*Key, error) {
+	var keys []*Key
+	for page := 1; ; page++ {
+		resp, err := c.sendRequest("GET", fmt.Sprintf("/key/list?team_id=%s&return_full_object=true&page=%d&size=100", url.QueryEscape(teamID), page), nil)
+		if err != nil {
+			return nil, err
+		}
+
+		data, _ := resp["keys"].([]interface{})
+		for _, k := range data {
+			record, ok := k.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			key, err := c.parseKeyResponse(record)
+			if err != nil {
+				return nil, err
+			}
+			// Older proxies ignore the team_id filter, so match on it here too
+			if key.TeamID == teamID {
+				keys = append(keys, key)
+			}
+		}
+
+		totalPages, _ := resp["total_pages"].(float64)
+		if len(data) == 0 || float64(page) >= totalPages {
+			return keys, nil
+		}
+	}
+}
+
+// MoveKeyToTeam changes the team that owns a key, leaving its other settings
+// untouched.
+func (c *Client) MoveKeyToTeam(keyID, teamID string) error {
+	payload := map[string]interface{}{
+		"key":     keyID,
+		"team_id": teamID,
+	}
+	_, err := c.sendRequest("POST", "/key/update", payload)
+	return err
+}
+
 func (c *Client) parseKeyResponse(resp map[string]interface{}) (*Key, error) {
 	if resp == nil {
 		return nil, fmt.Errorf("received nil response")
@@ -123,6 +271,10 @@ func (c *Client) parseKeyResponse(resp map[string]interface{}) (*Key, error) {
 			if s, ok := v.(string); ok {
 				createdKey.Key = s
 			}
+		case "token":
+			if s, ok := v.(string); ok {
+				createdKey.Token = s
+			}
 		case "models":
 			if models, ok := v.([]interface{}); ok {
 				createdKey.Models = make([]string, len(models))
@@ -138,7 +290,7 @@ func (c *Client) parseKeyResponse(resp map[string]interface{}) (*Key, error) {
 			}
 		case "max_budget":
 			if f, ok := v.(float64); ok {
-				createdKey.MaxBudget = f
+				createdKey.MaxBudget = &f
 			}
 		case "user_id":
 			if s, ok := v.(string); ok {
@@ -149,28 +301,40 @@ func (c *Client) parseKeyResponse(resp map[string]interface{}) (*Key, error) {
 				createdKey.TeamID = s
 			}
 		case "max_parallel_requests":
-			if i, ok := v.(float64); ok {
-				createdKey.MaxParallelRequests = int(i)
+			if f, ok := v.(float64); ok {
+				i := int(f)
+				createdKey.MaxParallelRequests = &i
 			}
 		case "metadata":
 			if m, ok := v.(map[string]interface{}); ok {
 				createdKey.Metadata = m
 			}
 		case "tpm_limit":
-			if i, ok := v.(float64); ok {
-				createdKey.TPMLimit = int(i)
+			if f, ok := v.(float64); ok {
+				i := int(f)
+				createdKey.TPMLimit = &i
 			}
 		case "rpm_limit":
-			if i, ok := v.(float64); ok {
-				createdKey.RPMLimit = int(i)
+			if f, ok := v.(float64); ok {
+				i := int(f)
+				createdKey.RPMLimit = &i
 			}
 		case "budget_duration":
 			if s, ok := v.(string); ok {
 				createdKey.BudgetDuration = s
 			}
+		case "allowed_cache_controls":
+			if controls, ok := v.([]interface{}); ok {
+				createdKey.AllowedCacheControls = make([]string, len(controls))
+				for i, control := range controls {
+					if s, ok := control.(string); ok {
+						createdKey.AllowedCacheControls[i] = s
+					}
+				}
+			}
 		case "soft_budget":
 			if f, ok := v.(float64); ok {
-				createdKey.SoftBudget = f
+				createdKey.SoftBudget = &f
 			}
 		case "key_alias":
 			if s, ok := v.(string); ok {
@@ -226,12 +390,132 @@ func (c *Client) parseKeyResponse(resp map[string]interface{}) (*Key, error) {
 					}
 				}
 			}
+		case "allowed_routes":
+			createdKey.AllowedRoutes = expandInterfaceStringList(v)
+		case "enforced_params":
+			createdKey.EnforcedParams = expandInterfaceStringList(v)
+		case "auto_rotate":
+			if b, ok := v.(bool); ok {
+				createdKey.AutoRotate = b
+			}
+		case "rotation_interval":
+			if s, ok := v.(string); ok {
+				createdKey.RotationInterval = s
+			}
+		case "created_at":
+			if s, ok := v.(string); ok {
+				createdKey.CreatedAt = s
+			}
+		case "last_rotation_at":
+			if s, ok := v.(string); ok {
+				createdKey.LastRotationAt = s
+			}
+		case "expires":
+			if s, ok := v.(string); ok {
+				createdKey.Expires = s
+			}
+		}
+	}
+
+	// The proxy stores tags, guardrails, enforced params and temporary budget
+	// increases inside the key's metadata
+	if createdKey.Metadata != nil {
+		if createdKey.Tags == nil {
+			createdKey.Tags = expandInterfaceStringList(createdKey.Metadata["tags"])
+		}
+		if createdKey.Guardrails == nil {
+			createdKey.Guardrails = expandInterfaceStringList(createdKey.Metadata["guardrails"])
+		}
+		if createdKey.EnforcedParams == nil {
+			createdKey.EnforcedParams = expandInterfaceStringList(createdKey.Metadata["enforced_params"])
+		}
+		if s, ok := createdKey.Metadata["service_account_id"].(string); ok {
+			createdKey.ServiceAccountID = s
+		}
+		if f, ok := createdKey.Metadata["temp_budget_increase"].(float64); ok {
+			createdKey.TempBudgetIncrease = &f
+		}
+		if s, ok := createdKey.Metadata["temp_budget_expiry"].(string); ok {
+			createdKey.TempBudgetExpiry = s
+		}
+		for _, managed := range []string{"tags", "guardrails", "enforced_params", "service_account_id", "temp_budget_increase", "temp_budget_expiry"} {
+			delete(createdKey.Metadata, managed)
 		}
 	}
 
 	return createdKey, nil
 }
 
+// withServiceAccountID returns a copy of metadata that includes the service
+// account ID, since updating a key's metadata replaces it entirely.
+func withServiceAccountID(metadata map[string]interface{}, serviceAccountID string) map[string]interface{} {
+	if serviceAccountID == "" {
+		return metadata
+	}
+
+	result := make(map[string]interface{}, len(metadata)+1)
+	for k, v := range metadata {
+		result[k] = v
+	}
+	result["service_account_id"] = serviceAccountID
+	return result
+}
+
+// redactedLogFields are request and response fields whose values are secrets,
+// such as generated keys and the credentials of team callbacks.
+var redactedLogFields = map[string]bool{
+	"key":           true,
+	"token":         true,
+	"callback_vars": true,
+}
+
+// redactJSON returns a JSON body for logging, with the values of
+// redactedLogFields replaced at any depth. Bodies that aren't JSON are
+// returned as they are.
+func redactJSON(body []byte) string {
+	var v interface{}
+	if err := json.Unmarshal(body, &v); err != nil {
+		return string(body)
+	}
+	redacted, err := json.Marshal(redactValue(v))
+	if err != nil {
+		return string(body)
+	}
+	return string(redacted)
+}
+
+// redactForLog returns v as redacted JSON for logging.
+func redactForLog(v interface{}) string {
+	body, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprintf("<%s>", err)
+	}
+	return redactJSON(body)
+}
+
+func redactValue(v interface{}) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		result := make(map[string]interface{}, len(v))
+		for k, val := range v {
+			if redactedLogFields[k] && val != nil {
+				result[k] = "REDACTED"
+			} else {
+				result[k] = redactValue(val)
+			}
+		}
+		return result
+	case []interface{}:
+		result := make([]interface{}, len(v))
+		for i, val := range v {
+			result[i] = redactValue(val)
+		}
+		return result
+	default:
+		return v
+	}
+}
+
 func (c *Client) sendRequest(method, path string, body interface{}) (map[string]interface{}, error) {
 	url := c.APIBase + path
 
@@ -243,7 +527,7 @@ func (c *Client) sendRequest(method, path string, body interface{}) (map[string]
 		if err != nil {
 			return nil, fmt.Errorf("error marshaling request body: %v", err)
 		}
-		log.Printf("Making %s request to %s with body:\n%s", method, url, string(jsonBody))
+		log.Printf("Making %s request to %s with body:\n%s", method, url, redactJSON(jsonBody))
 		req, err = http.NewRequest(method, url, bytes.NewBuffer(jsonBody))
 	} else {
 		log.Printf("Making %s request to %s", method, url)
@@ -270,10 +554,10 @@ func (c *Client) sendRequest(method, path string, body interface{}) (map[string]
 	}
 
 	log.Printf("Response status: %d", resp.StatusCode)
-	log.Printf("Response body: %s", string(bodyBytes))
+	log.Printf("Response body: %s", redactJSON(bodyBytes))
 
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("API request failed with status code %d: %s", resp.StatusCode, string(bodyBytes))
+		return nil, &APIError{StatusCode: resp.StatusCode, Body: string(bodyBytes)}
 	}
 
 	var result map[string]interface{}
@@ -0,0 +1,77 @@
+package litellm
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	"github.com/ProtonMail/go-crypto/openpgp"
+	"github.com/ProtonMail/go-crypto/openpgp/packet"
+)
+
+const keybasePrefix = "keybase:"
+
+// retrievePGPKey resolves a pgp_key value to a base64-encoded public key.
+// "keybase:<name>" references are looked up in keyring instead of being
+// fetched from keybase.io, so that plans work offline.
+func retrievePGPKey(pgpKey string, keyring map[string]interface{}) (string, error) {
+	if !strings.HasPrefix(pgpKey, keybasePrefix) {
+		return pgpKey, nil
+	}
+
+	name := strings.TrimPrefix(pgpKey, keybasePrefix)
+	publicKey, ok := keyring[name].(string)
+	if !ok || publicKey == "" {
+		return "", fmt.Errorf("pgp_key %q: no public key for %q in pgp_keyring", pgpKey, name)
+	}
+	return publicKey, nil
+}
+
+// readPGPEntity parses a base64-encoded public key, either binary or ASCII-armored.
+func readPGPEntity(encodedKey string) (*openpgp.Entity, error) {
+	decoded, err := base64.StdEncoding.DecodeString(strings.TrimSpace(encodedKey))
+	if err != nil {
+		return nil, fmt.Errorf("error decoding PGP public key: %s", err)
+	}
+
+	var entities openpgp.EntityList
+	if bytes.HasPrefix(bytes.TrimSpace(decoded), []byte("-----BEGIN")) {
+		entities, err = openpgp.ReadArmoredKeyRing(bytes.NewReader(decoded))
+	} else {
+		entities, err = openpgp.ReadKeyRing(bytes.NewReader(decoded))
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error parsing PGP public key: %s", err)
+	}
+	if len(entities) != 1 {
+		return nil, fmt.Errorf("expected exactly one PGP public key, found %d", len(entities))
+	}
+	return entities[0], nil
+}
+
+// encryptValue encrypts value for the base64-encoded public key and returns
+// the key's fingerprint and the base64-encoded ciphertext, which can be
+// decrypted with `base64 -d | gpg --decrypt`.
+func encryptValue(encodedKey, value string) (string, string, error) {
+	entity, err := readPGPEntity(encodedKey)
+	if err != nil {
+		return "", "", err
+	}
+
+	var buf bytes.Buffer
+	w, err := openpgp.Encrypt(&buf, openpgp.EntityList{entity}, nil, nil, &packet.Config{})
+	if err != nil {
+		return "", "", fmt.Errorf("error encrypting value: %s", err)
+	}
+	if _, err := w.Write([]byte(value)); err != nil {
+		return "", "", fmt.Errorf("error encrypting value: %s", err)
+	}
+	if err := w.Close(); err != nil {
+		return "", "", fmt.Errorf("error encrypting value: %s", err)
+	}
+
+	fingerprint := hex.EncodeToString(entity.PrimaryKey.Fingerprint)
+	return fingerprint, base64.StdEncoding.EncodeToString(buf.Bytes()), nil
+}
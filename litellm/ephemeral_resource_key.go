@@ -0,0 +1,198 @@
+package litellm
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/ephemeral"
+	"github.com/hashicorp/terraform-plugin-framework/ephemeral/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// defaultEphemeralKeyDuration is used when no duration is configured, so that a
+// key leaked from a crashed run expires on its own.
+const defaultEphemeralKeyDuration = "1h"
+
+// keyEphemeralResource generates a LiteLLM key for the duration of a single
+// Terraform run and deletes it when Terraform closes the resource.
+type keyEphemeralResource struct {
+	client *Client
+}
+
+type keyEphemeralResourceModel struct {
+	Models    types.List    `tfsdk:"models"`
+	MaxBudget types.Float64 `tfsdk:"max_budget"`
+	UserID    types.String  `tfsdk:"user_id"`
+	TeamID    types.String  `tfsdk:"team_id"`
+	KeyAlias  types.String  `tfsdk:"key_alias"`
+	Duration  types.String  `tfsdk:"duration"`
+	Metadata  types.Map     `tfsdk:"metadata"`
+	TPMLimit  types.Int64   `tfsdk:"tpm_limit"`
+	RPMLimit  types.Int64   `tfsdk:"rpm_limit"`
+	Key       types.String  `tfsdk:"key"`
+	Token     types.String  `tfsdk:"token"`
+}
+
+// keyEphemeralPrivate is stored in the ephemeral resource's private data so
+// that Close knows which key to delete. Only the hashed token is kept, so the
+// plaintext key isn't persisted between calls.
+type keyEphemeralPrivate struct {
+	Token string `json:"token"`
+}
+
+var _ ephemeral.EphemeralResourceWithConfigure = &keyEphemeralResource{}
+var _ ephemeral.EphemeralResourceWithClose = &keyEphemeralResource{}
+
+func newKeyEphemeralResource() ephemeral.EphemeralResource {
+	return &keyEphemeralResource{}
+}
+
+func (r *keyEphemeralResource) Metadata(ctx context.Context, req ephemeral.MetadataRequest, resp *ephemeral.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_key"
+}
+
+func (r *keyEphemeralResource) Schema(ctx context.Context, req ephemeral.SchemaRequest, resp *ephemeral.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Generates a short-lived LiteLLM key that is deleted when Terraform closes the ephemeral resource.",
+		Attributes: map[string]schema.Attribute{
+			"models": schema.ListAttribute{
+				ElementType: types.StringType,
+				Optional:    true,
+				Description: "List of models that can be used with this key.",
+			},
+			"max_budget": schema.Float64Attribute{
+				Optional:    true,
+				Description: "Maximum budget for this key.",
+			},
+			"user_id": schema.StringAttribute{
+				Optional:    true,
+				Description: "User ID associated with this key.",
+			},
+			"team_id": schema.StringAttribute{
+				Optional:    true,
+				Description: "Team ID associated with this key.",
+			},
+			"key_alias": schema.StringAttribute{
+				Optional:    true,
+				Description: "Alias for this key.",
+			},
+			"duration": schema.StringAttribute{
+				Optional:    true,
+				Description: "Duration for which this key is valid, e.g. \"30m\" or \"1h\". Defaults to \"" + defaultEphemeralKeyDuration + "\".",
+			},
+			"metadata": schema.MapAttribute{
+				ElementType: types.StringType,
+				Optional:    true,
+				Description: "Metadata associated with this key.",
+			},
+			"tpm_limit": schema.Int64Attribute{
+				Optional:    true,
+				Description: "Tokens per minute limit for this key.",
+			},
+			"rpm_limit": schema.Int64Attribute{
+				Optional:    true,
+				Description: "Requests per minute limit for this key.",
+			},
+			"key": schema.StringAttribute{
+				Computed:    true,
+				Sensitive:   true,
+				Description: "The generated API key.",
+			},
+			"token": schema.StringAttribute{
+				Computed:    true,
+				Description: "The hashed token of the generated key.",
+			},
+		},
+	}
+}
+
+func (r *keyEphemeralResource) Configure(ctx context.Context, req ephemeral.ConfigureRequest, resp *ephemeral.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*Client)
+	if !ok {
+		resp.Diagnostics.AddError("Unexpected provider data", fmt.Sprintf("expected *Client, got %T", req.ProviderData))
+		return
+	}
+
+	r.client = client
+}
+
+func (r *keyEphemeralResource) Open(ctx context.Context, req ephemeral.OpenRequest, resp *ephemeral.OpenResponse) {
+	var data keyEphemeralResourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	key := &Key{
+		MaxBudget: data.MaxBudget.ValueFloat64Pointer(),
+		UserID:    data.UserID.ValueString(),
+		TeamID:    data.TeamID.ValueString(),
+		KeyAlias:  data.KeyAlias.ValueString(),
+		Duration:  GetStringValue(data.Duration.ValueString(), defaultEphemeralKeyDuration),
+	}
+	if !data.TPMLimit.IsNull() {
+		tpmLimit := int(data.TPMLimit.ValueInt64())
+		key.TPMLimit = &tpmLimit
+	}
+	if !data.RPMLimit.IsNull() {
+		rpmLimit := int(data.RPMLimit.ValueInt64())
+		key.RPMLimit = &rpmLimit
+	}
+	if !data.Models.IsNull() {
+		resp.Diagnostics.Append(data.Models.ElementsAs(ctx, &key.Models, false)...)
+	}
+	if !data.Metadata.IsNull() {
+		metadata := make(map[string]string)
+		resp.Diagnostics.Append(data.Metadata.ElementsAs(ctx, &metadata, false)...)
+		key.Metadata = make(map[string]interface{}, len(metadata))
+		for k, v := range metadata {
+			key.Metadata[k] = v
+		}
+	}
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	createdKey, err := r.client.CreateKey(key)
+	if err != nil {
+		resp.Diagnostics.AddError("Error creating key", err.Error())
+		return
+	}
+
+	token := GetStringValue(createdKey.Token, hashToken(createdKey.Key))
+	private, err := json.Marshal(keyEphemeralPrivate{Token: token})
+	if err != nil {
+		resp.Diagnostics.AddError("Error storing key private data", err.Error())
+		return
+	}
+	resp.Diagnostics.Append(resp.Private.SetKey(ctx, "token", private)...)
+
+	// The default duration is only applied to the request, so that the
+	// result matches the configuration
+	data.Key = types.StringValue(createdKey.Key)
+	data.Token = types.StringValue(token)
+	resp.Diagnostics.Append(resp.Result.Set(ctx, &data)...)
+}
+
+func (r *keyEphemeralResource) Close(ctx context.Context, req ephemeral.CloseRequest, resp *ephemeral.CloseResponse) {
+	raw, diags := req.Private.GetKey(ctx, "token")
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() || raw == nil {
+		return
+	}
+
+	var private keyEphemeralPrivate
+	if err := json.Unmarshal(raw, &private); err != nil {
+		resp.Diagnostics.AddError("Error reading key private data", err.Error())
+		return
+	}
+
+	if err := r.client.DeleteKey(private.Token); err != nil {
+		resp.Diagnostics.AddError("Error deleting key", err.Error())
+	}
+}
@@ -0,0 +1,856 @@
+package litellm
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/go-cty/cty"
+	ctyjson "github.com/hashicorp/go-cty/cty/json"
+	"github.com/hashicorp/go-cty/cty/msgpack"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+// fakeProxy is a minimal in-memory stand-in for the LiteLLM proxy API. It
+// stores keys the way the proxy does, including moving tags and guardrails
+// into the key's metadata and never returning duration or send_invite_email.
+// Teams that still own keys can't be deleted, and members' budgets are kept
+// in memberships that /team/info returns next to the team, and team callbacks
+// in the team's metadata. Models are stored as they were sent.
+type fakeProxy struct {
+	mu          sync.Mutex
+	server      *httptest.Server
+	keys        map[string]map[string]interface{}
+	teams       map[string]map[string]interface{}
+	models      map[string]map[string]interface{}
+	memberships map[string]map[string]interface{}
+	requests    map[string][]map[string]interface{}
+	counter     int
+
+	// failNext fails the given number of requests to each path with a
+	// server error
+	failNext map[string]int
+}
+
+func newFakeProxy(t *testing.T) *fakeProxy {
+	p := &fakeProxy{
+		keys:        make(map[string]map[string]interface{}),
+		teams:       make(map[string]map[string]interface{}),
+		models:      make(map[string]map[string]interface{}),
+		memberships: make(map[string]map[string]interface{}),
+		requests:    make(map[string][]map[string]interface{}),
+		failNext:    make(map[string]int),
+	}
+	p.server = httptest.NewServer(http.HandlerFunc(p.handle))
+	t.Cleanup(p.server.Close)
+	return p
+}
+
+func (p *fakeProxy) client() *Client {
+	return NewClient(p.server.URL, "sk-master")
+}
+
+// lastRequest returns the body of the most recent request to path.
+func (p *fakeProxy) lastRequest(path string) map[string]interface{} {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	requests := p.requests[path]
+	if len(requests) == 0 {
+		return nil
+	}
+	return requests[len(requests)-1]
+}
+
+func (p *fakeProxy) handle(w http.ResponseWriter, r *http.Request) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	var body map[string]interface{}
+	if r.Method == http.MethodPost {
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		p.requests[r.URL.Path] = append(p.requests[r.URL.Path], body)
+	}
+	if p.failNext[r.URL.Path] > 0 {
+		p.failNext[r.URL.Path]--
+		http.Error(w, `{"detail":{"error":"Internal server error"}}`, http.StatusInternalServerError)
+		return
+	}
+
+	switch {
+	case r.URL.Path == "/key/generate":
+		p.generateKey(w, body)
+	case r.URL.Path == "/key/service-account/generate":
+		if teamID, _ := body["team_id"].(string); teamID == "" {
+			http.Error(w, `{"detail":{"error":"team_id is required for service account keys"}}`, http.StatusBadRequest)
+			return
+		}
+		p.generateKey(w, body)
+	case r.URL.Path == "/key/info":
+		p.keyInfo(w, r.URL.Query().Get("key"))
+	case r.URL.Path == "/key/update":
+		p.updateKey(w, body)
+	case r.URL.Path == "/key/delete":
+		p.deleteKeys(w, body)
+	case r.URL.Path == "/key/list":
+		p.listKeys(w, r)
+	case strings.HasPrefix(r.URL.Path, "/key/") && strings.HasSuffix(r.URL.Path, "/regenerate"):
+		p.regenerateKey(w, strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/key/"), "/regenerate"))
+	case r.URL.Path == "/team/new":
+		p.newTeam(w, body)
+	case r.URL.Path == "/team/info":
+		p.teamInfo(w, r.URL.Query().Get("team_id"))
+	case r.URL.Path == "/team/update", r.URL.Path == "/team/permissions_update":
+		p.updateTeam(w, body, body)
+	case r.URL.Path == "/team/block":
+		p.updateTeam(w, body, map[string]interface{}{"blocked": true})
+	case r.URL.Path == "/team/unblock":
+		p.updateTeam(w, body, map[string]interface{}{"blocked": false})
+	case r.URL.Path == "/team/model/add":
+		p.changeTeamModels(w, body, true)
+	case r.URL.Path == "/team/model/delete":
+		p.changeTeamModels(w, body, false)
+	case strings.HasPrefix(r.URL.Path, "/team/") && strings.HasSuffix(r.URL.Path, "/callback"):
+		teamID := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/team/"), "/callback")
+		if r.Method == http.MethodPost {
+			p.addTeamCallback(w, teamID, body)
+		} else {
+			p.teamCallbacks(w, teamID)
+		}
+	case r.URL.Path == "/team/delete":
+		p.deleteTeams(w, body)
+	case r.URL.Path == "/team/member_add":
+		p.addTeamMembers(w, body)
+	case r.URL.Path == "/team/member_update":
+		p.updateTeamMember(w, body)
+	case r.URL.Path == "/team/member_delete":
+		p.deleteTeamMember(w, body)
+	case r.URL.Path == "/model/new":
+		p.saveModel(w, body, true)
+	case r.URL.Path == "/model/update":
+		p.saveModel(w, body, false)
+	case r.URL.Path == "/model/info":
+		p.modelInfo(w, r.URL.Query().Get("litellm_model_id"))
+	case r.URL.Path == "/model/delete":
+		p.deleteModel(w, body)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func (p *fakeProxy) newSecret() string {
+	p.counter++
+	return fmt.Sprintf("sk-fake-%d", p.counter)
+}
+
+// lookupToken accepts either a plaintext key or a hashed token.
+func lookupToken(key string) string {
+	if strings.HasPrefix(key, "sk-") {
+		return hashToken(key)
+	}
+	return key
+}
+
+func (p *fakeProxy) generateKey(w http.ResponseWriter, body map[string]interface{}) {
+	secret, _ := body["key"].(string)
+	if secret == "" {
+		secret = p.newSecret()
+	}
+	token := hashToken(secret)
+
+	record := map[string]interface{}{"token": token, "spend": 0.0}
+	if _, ok := body["metadata"]; !ok {
+		record["metadata"] = map[string]interface{}{}
+	}
+	applyKeyFields(record, body)
+	p.keys[token] = record
+
+	writeJSON(w, mergeMaps(record, map[string]interface{}{"key": secret}))
+}
+
+func (p *fakeProxy) keyInfo(w http.ResponseWriter, key string) {
+	record, ok := p.keys[lookupToken(key)]
+	if !ok {
+		http.Error(w, `{"detail":{"error":"Key not found in database"}}`, http.StatusNotFound)
+		return
+	}
+	writeJSON(w, map[string]interface{}{"key": key, "info": record})
+}
+
+func (p *fakeProxy) updateKey(w http.ResponseWriter, body map[string]interface{}) {
+	key, _ := body["key"].(string)
+	record, ok := p.keys[lookupToken(key)]
+	if !ok {
+		http.Error(w, `{"detail":{"error":"Key not found"}}`, http.StatusNotFound)
+		return
+	}
+	applyKeyFields(record, body)
+	writeJSON(w, record)
+}
+
+func (p *fakeProxy) deleteKeys(w http.ResponseWriter, body map[string]interface{}) {
+	keys, _ := body["keys"].([]interface{})
+	for _, k := range keys {
+		delete(p.keys, lookupToken(k.(string)))
+	}
+	writeJSON(w, map[string]interface{}{"deleted_keys": keys})
+}
+
+func (p *fakeProxy) listKeys(w http.ResponseWriter, r *http.Request) {
+	keys := []interface{}{}
+	for _, record := range p.keys {
+		if alias := r.URL.Query().Get("key_alias"); alias != "" && record["key_alias"] != alias {
+			continue
+		}
+		if teamID := r.URL.Query().Get("team_id"); teamID != "" && record["team_id"] != teamID {
+			continue
+		}
+		keys = append(keys, record)
+	}
+	writeJSON(w, map[string]interface{}{"keys": keys, "total_count": len(keys)})
+}
+
+func (p *fakeProxy) regenerateKey(w http.ResponseWriter, key string) {
+	record, ok := p.keys[lookupToken(key)]
+	if !ok {
+		http.Error(w, `{"detail":{"error":"Key not found"}}`, http.StatusNotFound)
+		return
+	}
+	delete(p.keys, lookupToken(key))
+
+	secret := p.newSecret()
+	record["token"] = hashToken(secret)
+	p.keys[hashToken(secret)] = record
+
+	writeJSON(w, mergeMaps(record, map[string]interface{}{"key": secret}))
+}
+
+// defaultTeamMemberPermissions are the permissions new teams get.
+var defaultTeamMemberPermissions = []interface{}{"/key/info", "/key/health"}
+
+func (p *fakeProxy) newTeam(w http.ResponseWriter, body map[string]interface{}) {
+	teamID, _ := body["team_id"].(string)
+	if _, ok := p.teams[teamID]; ok || teamID == "" {
+		http.Error(w, `{"detail":{"error":"Team id already exists"}}`, http.StatusBadRequest)
+		return
+	}
+
+	record := map[string]interface{}{
+		"blocked":                 false,
+		"metadata":                map[string]interface{}{},
+		"team_member_permissions": defaultTeamMemberPermissions,
+		"members_with_roles":      []interface{}{},
+	}
+	applyTeamFields(record, body)
+	p.teams[teamID] = record
+
+	writeJSON(w, record)
+}
+
+func (p *fakeProxy) teamInfo(w http.ResponseWriter, teamID string) {
+	record, ok := p.teams[teamID]
+	if !ok {
+		http.Error(w, `{"detail":{"error":"Team not found"}}`, http.StatusNotFound)
+		return
+	}
+	memberships := []interface{}{}
+	for _, membership := range p.memberships {
+		if membership["team_id"] == teamID {
+			memberships = append(memberships, membership)
+		}
+	}
+	writeJSON(w, map[string]interface{}{"team_id": teamID, "team_info": record, "team_memberships": memberships})
+}
+
+// findMember returns the index of the member identified by the request body
+// in the team's members_with_roles, or -1.
+func findMember(team, body map[string]interface{}) int {
+	userID, _ := body["user_id"].(string)
+	userEmail, _ := body["user_email"].(string)
+	for i, m := range team["members_with_roles"].([]interface{}) {
+		member := m.(map[string]interface{})
+		if (userID != "" && member["user_id"] == userID) || (userID == "" && userEmail != "" && member["user_email"] == userEmail) {
+			return i
+		}
+	}
+	return -1
+}
+
+func (p *fakeProxy) addTeamMembers(w http.ResponseWriter, body map[string]interface{}) {
+	teamID, _ := body["team_id"].(string)
+	team, ok := p.teams[teamID]
+	if !ok {
+		http.Error(w, `{"detail":{"error":"Team not found"}}`, http.StatusNotFound)
+		return
+	}
+
+	members, _ := body["member"].([]interface{})
+	for _, m := range members {
+		member := m.(map[string]interface{})
+		if findMember(team, member) >= 0 {
+			http.Error(w, `{"detail":{"error":"User is already a member of the team"}}`, http.StatusBadRequest)
+			return
+		}
+		if member["user_id"] == nil {
+			p.counter++
+			member["user_id"] = fmt.Sprintf("default-user-%d", p.counter)
+		}
+		team["members_with_roles"] = append(team["members_with_roles"].([]interface{}), member)
+		p.setMembershipBudget(teamID, member["user_id"].(string), body)
+	}
+	writeJSON(w, team)
+}
+
+func (p *fakeProxy) updateTeamMember(w http.ResponseWriter, body map[string]interface{}) {
+	teamID, _ := body["team_id"].(string)
+	team, ok := p.teams[teamID]
+	if !ok {
+		http.Error(w, `{"detail":{"error":"Team not found"}}`, http.StatusNotFound)
+		return
+	}
+	i := findMember(team, body)
+	if i < 0 {
+		http.Error(w, `{"detail":{"error":"User is not a member of the team"}}`, http.StatusBadRequest)
+		return
+	}
+
+	member := team["members_with_roles"].([]interface{})[i].(map[string]interface{})
+	if role, ok := body["role"]; ok {
+		member["role"] = role
+	}
+	p.setMembershipBudget(teamID, member["user_id"].(string), body)
+	writeJSON(w, member)
+}
+
+func (p *fakeProxy) deleteTeamMember(w http.ResponseWriter, body map[string]interface{}) {
+	teamID, _ := body["team_id"].(string)
+	team, ok := p.teams[teamID]
+	if !ok {
+		http.Error(w, `{"detail":{"error":"Team not found"}}`, http.StatusNotFound)
+		return
+	}
+	i := findMember(team, body)
+	if i < 0 {
+		http.Error(w, `{"detail":{"error":"User is not a member of the team"}}`, http.StatusBadRequest)
+		return
+	}
+
+	members := team["members_with_roles"].([]interface{})
+	member := members[i].(map[string]interface{})
+	delete(p.memberships, teamID+":"+member["user_id"].(string))
+	team["members_with_roles"] = append(members[:i:i], members[i+1:]...)
+	writeJSON(w, team)
+}
+
+// setMembershipBudget stores the budget fields of the request body on the
+// member's membership.
+func (p *fakeProxy) setMembershipBudget(teamID, userID string, body map[string]interface{}) {
+	fields := map[string]string{"max_budget_in_team": "max_budget", "tpm_limit": "tpm_limit", "rpm_limit": "rpm_limit"}
+	for field, budgetField := range fields {
+		v, ok := body[field]
+		if !ok {
+			continue
+		}
+
+		id := teamID + ":" + userID
+		membership, ok := p.memberships[id]
+		if !ok {
+			membership = map[string]interface{}{"team_id": teamID, "user_id": userID, "spend": 0.0}
+			p.memberships[id] = membership
+		}
+		budget, _ := membership["litellm_budget_table"].(map[string]interface{})
+		if budget == nil {
+			budget = map[string]interface{}{}
+			membership["litellm_budget_table"] = budget
+		}
+		budget[budgetField] = v
+	}
+}
+
+// updateTeam applies fields to the team identified by the request body.
+func (p *fakeProxy) updateTeam(w http.ResponseWriter, body, fields map[string]interface{}) {
+	teamID, _ := body["team_id"].(string)
+	record, ok := p.teams[teamID]
+	if !ok {
+		http.Error(w, `{"detail":{"error":"Team not found"}}`, http.StatusNotFound)
+		return
+	}
+	applyTeamFields(record, fields)
+	writeJSON(w, record)
+}
+
+// teamMemberBudgetFields maps the defaults for new team members to the fields
+// of the budget table the proxy keeps them in.
+var teamMemberBudgetFields = map[string]string{
+	"team_member_budget":    "max_budget",
+	"team_member_rpm_limit": "rpm_limit",
+	"team_member_tpm_limit": "tpm_limit",
+}
+
+// applyTeamFields copies request fields onto a stored team the way the proxy
+// does: metadata is replaced, null defaults for new team members are ignored,
+// and the defaults are kept in a budget table and in the team's metadata.
+func applyTeamFields(record, fields map[string]interface{}) {
+	if metadata, ok := fields["metadata"].(map[string]interface{}); ok {
+		record["metadata"] = mergeMaps(metadata)
+	}
+	for k, v := range fields {
+		switch {
+		case k == "metadata":
+		case teamMemberBudgetFields[k] != "":
+			if v == nil {
+				continue
+			}
+			budget, _ := record["team_member_budget_table"].(map[string]interface{})
+			if budget == nil {
+				budget = map[string]interface{}{}
+				record["team_member_budget_table"] = budget
+			}
+			budget[teamMemberBudgetFields[k]] = v
+		case k == "team_member_key_duration":
+			if v == nil {
+				continue
+			}
+			metadata, _ := record["metadata"].(map[string]interface{})
+			if metadata == nil {
+				metadata = map[string]interface{}{}
+				record["metadata"] = metadata
+			}
+			metadata[k] = v
+		default:
+			record[k] = v
+		}
+	}
+}
+
+// changeTeamModels adds models to or removes models from a team, leaving its
+// other models alone.
+func (p *fakeProxy) changeTeamModels(w http.ResponseWriter, body map[string]interface{}, add bool) {
+	teamID, _ := body["team_id"].(string)
+	record, ok := p.teams[teamID]
+	if !ok {
+		http.Error(w, `{"detail":{"error":"Team not found"}}`, http.StatusNotFound)
+		return
+	}
+
+	changed, _ := body["models"].([]interface{})
+	models := []interface{}{}
+	existing, _ := record["models"].([]interface{})
+	for _, model := range existing {
+		if add || !containsInterface(changed, model) {
+			models = append(models, model)
+		}
+	}
+	if add {
+		for _, model := range changed {
+			if !containsInterface(models, model) {
+				models = append(models, model)
+			}
+		}
+	}
+	record["models"] = models
+	writeJSON(w, record)
+}
+
+func containsInterface(list []interface{}, v interface{}) bool {
+	for _, item := range list {
+		if item == v {
+			return true
+		}
+	}
+	return false
+}
+
+// teamCallbackSettings returns the callback settings stored in the team's
+// metadata, creating them if needed.
+func teamCallbackSettings(team map[string]interface{}) map[string]interface{} {
+	metadata, _ := team["metadata"].(map[string]interface{})
+	if metadata == nil {
+		metadata = map[string]interface{}{}
+		team["metadata"] = metadata
+	}
+	settings, _ := metadata["callback_settings"].(map[string]interface{})
+	if settings == nil {
+		settings = map[string]interface{}{}
+		metadata["callback_settings"] = settings
+	}
+	for _, field := range []string{"success_callback", "failure_callback"} {
+		if _, ok := settings[field].([]interface{}); !ok {
+			settings[field] = []interface{}{}
+		}
+	}
+	if _, ok := settings["callback_vars"].(map[string]interface{}); !ok {
+		settings["callback_vars"] = map[string]interface{}{}
+	}
+	return settings
+}
+
+func (p *fakeProxy) teamCallbacks(w http.ResponseWriter, teamID string) {
+	team, ok := p.teams[teamID]
+	if !ok {
+		http.Error(w, `{"detail":{"error":"Team not found"}}`, http.StatusNotFound)
+		return
+	}
+	settings := teamCallbackSettings(team)
+	writeJSON(w, map[string]interface{}{
+		"team_id": teamID,
+		"data": map[string]interface{}{
+			"success_callbacks": settings["success_callback"],
+			"failure_callbacks": settings["failure_callback"],
+			"callback_vars":     settings["callback_vars"],
+		},
+	})
+}
+
+// addTeamCallback adds a callback to the team's callback settings. Like the
+// proxy, it refuses to add a callback that is already configured.
+func (p *fakeProxy) addTeamCallback(w http.ResponseWriter, teamID string, body map[string]interface{}) {
+	team, ok := p.teams[teamID]
+	if !ok {
+		http.Error(w, `{"detail":{"error":"Team not found"}}`, http.StatusNotFound)
+		return
+	}
+	settings := teamCallbackSettings(team)
+
+	name := body["callback_name"]
+	fields := map[string][]string{
+		"success":             {"success_callback"},
+		"failure":             {"failure_callback"},
+		"success_and_failure": {"success_callback", "failure_callback"},
+	}[body["callback_type"].(string)]
+	for _, field := range fields {
+		if containsInterface(settings[field].([]interface{}), name) {
+			http.Error(w, `{"detail":{"error":"callback_name already exists in `+field+`"}}`, http.StatusBadRequest)
+			return
+		}
+	}
+	for _, field := range fields {
+		settings[field] = append(settings[field].([]interface{}), name)
+	}
+	vars, _ := body["callback_vars"].(map[string]interface{})
+	for k, v := range vars {
+		settings["callback_vars"].(map[string]interface{})[k] = v
+	}
+	writeJSON(w, map[string]interface{}{"status": "success"})
+}
+
+func (p *fakeProxy) deleteTeams(w http.ResponseWriter, body map[string]interface{}) {
+	teamIDs, _ := body["team_ids"].([]interface{})
+	for _, teamID := range teamIDs {
+		for _, record := range p.keys {
+			if record["team_id"] == teamID {
+				http.Error(w, `{"detail":{"error":"Team still has keys"}}`, http.StatusBadRequest)
+				return
+			}
+		}
+	}
+	for _, teamID := range teamIDs {
+		delete(p.teams, teamID.(string))
+	}
+	writeJSON(w, map[string]interface{}{"deleted_teams": teamIDs})
+}
+
+// modelNotFound is the error the proxy returns for an unknown model ID.
+const modelNotFound = `{"error":{"message":"model not found"}}`
+
+func (p *fakeProxy) saveModel(w http.ResponseWriter, body map[string]interface{}, create bool) {
+	info, _ := body["model_info"].(map[string]interface{})
+	id, _ := info["id"].(string)
+	existing, ok := p.models[id]
+	if !ok && !create {
+		http.Error(w, modelNotFound, http.StatusBadRequest)
+		return
+	}
+	// Like the proxy, keep metadata that isn't sent
+	if existingInfo, _ := existing["model_info"].(map[string]interface{}); existingInfo != nil && info["metadata"] == nil {
+		info["metadata"] = existingInfo["metadata"]
+	}
+	p.models[id] = body
+	writeJSON(w, body)
+}
+
+func (p *fakeProxy) modelInfo(w http.ResponseWriter, id string) {
+	record, ok := p.models[id]
+	if !ok {
+		http.Error(w, modelNotFound, http.StatusBadRequest)
+		return
+	}
+	writeJSON(w, record)
+}
+
+func (p *fakeProxy) deleteModel(w http.ResponseWriter, body map[string]interface{}) {
+	id, _ := body["id"].(string)
+	if _, ok := p.models[id]; !ok {
+		http.Error(w, modelNotFound, http.StatusBadRequest)
+		return
+	}
+	delete(p.models, id)
+	writeJSON(w, map[string]interface{}{"id": id})
+}
+
+// applyKeyFields copies request fields onto a stored key the way the proxy
+// does: tags, guardrails, enforced params and temporary budget increases live
+// in metadata, duration is turned into an expiry timestamp, key_type is turned
+// into allowed routes, and send_invite_email is not stored. Metadata sent in
+// the request replaces the stored metadata, apart from tags and guardrails.
+func applyKeyFields(record, body map[string]interface{}) {
+	if requested, ok := body["metadata"].(map[string]interface{}); ok {
+		metadata := mergeMaps(requested)
+		if existing, ok := record["metadata"].(map[string]interface{}); ok {
+			for _, managed := range []string{"tags", "guardrails"} {
+				if _, ok := metadata[managed]; !ok && existing[managed] != nil {
+					metadata[managed] = existing[managed]
+				}
+			}
+		}
+		record["metadata"] = metadata
+	}
+
+	for k, v := range body {
+		switch k {
+		case "key", "send_invite_email", "metadata":
+		case "duration":
+			if duration, _ := v.(string); duration != "" {
+				expires, _ := addLiteLLMDuration(time.Now(), duration)
+				record["expires"] = expires.UTC().Format("2006-01-02T15:04:05.000000")
+			}
+		case "key_type":
+			if routes, ok := keyTypeRoutes[v.(string)]; ok {
+				record["allowed_routes"] = routes
+			}
+		case "tags", "guardrails", "enforced_params", "temp_budget_increase", "temp_budget_expiry":
+			metadata, _ := record["metadata"].(map[string]interface{})
+			if metadata == nil {
+				metadata = map[string]interface{}{}
+				record["metadata"] = metadata
+			}
+			metadata[k] = v
+		default:
+			record[k] = v
+		}
+	}
+}
+
+func mergeMaps(maps ...map[string]interface{}) map[string]interface{} {
+	result := make(map[string]interface{})
+	for _, m := range maps {
+		for k, v := range m {
+			result[k] = v
+		}
+	}
+	return result
+}
+
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(v)
+}
+
+// planResource returns the diff between state and config for r. The prior
+// state and the diff carry the raw configuration, as they would from
+// Terraform, so that the resource can tell unset attributes apart from zero
+// values.
+func planResource(t *testing.T, r *schema.Resource, state *terraform.InstanceState, config map[string]interface{}, meta interface{}) *terraform.InstanceDiff {
+	t.Helper()
+
+	b, err := json.Marshal(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rawConfig, err := ctyjson.Unmarshal(b, r.CoreConfigSchema().ImpliedType())
+	if err != nil {
+		t.Fatalf("error converting config: %s", err)
+	}
+	if state != nil {
+		state = state.DeepCopy()
+		state.RawConfig = rawConfig
+	}
+
+	diff, err := r.Diff(context.Background(), state, terraform.NewResourceConfigRaw(config), meta)
+	if err != nil {
+		t.Fatalf("error planning: %s", err)
+	}
+	if diff == nil {
+		return nil
+	}
+	diff.RawConfig = rawConfig
+	return diff
+}
+
+// applyResource plans and applies config on top of state, the way Terraform
+// would for a create or an in-place update.
+func applyResource(t *testing.T, r *schema.Resource, state *terraform.InstanceState, config map[string]interface{}, meta interface{}) *terraform.InstanceState {
+	t.Helper()
+
+	diff := planResource(t, r, state, config, meta)
+	if state != nil && diff.RequiresNew() {
+		t.Fatalf("expected an in-place update, got a replacement: %v", diff)
+	}
+
+	newState, diags := r.Apply(context.Background(), state, diff, meta)
+	if diags.HasError() {
+		t.Fatalf("error applying: %v", diags)
+	}
+	return newState
+}
+
+// destroyResource destroys the resource in state and returns the error, if any.
+func destroyResource(t *testing.T, r *schema.Resource, state *terraform.InstanceState, meta interface{}) error {
+	t.Helper()
+
+	_, diags := r.Apply(context.Background(), state, &terraform.InstanceDiff{Destroy: true}, meta)
+	if diags.HasError() {
+		return fmt.Errorf("%s", diags[0].Summary)
+	}
+	return nil
+}
+
+// refreshResource reads the resource back from the API.
+func refreshResource(t *testing.T, r *schema.Resource, state *terraform.InstanceState, meta interface{}) *terraform.InstanceState {
+	t.Helper()
+
+	newState, diags := r.RefreshWithoutUpgrade(context.Background(), state, meta)
+	if diags.HasError() {
+		t.Fatalf("error refreshing: %v", diags)
+	}
+	return newState
+}
+
+// assertNoDiff fails if planning config against state shows any changes.
+func assertNoDiff(t *testing.T, r *schema.Resource, state *terraform.InstanceState, config map[string]interface{}, meta interface{}) {
+	t.Helper()
+
+	if diff := planResource(t, r, state, config, meta); diff != nil && !diff.Empty() {
+		t.Fatalf("expected no changes after refresh, got: %v", diff)
+	}
+}
+
+// applyThroughProtocol plans and applies config for the resource type over
+// the plugin protocol, the way Terraform does, and returns the new state.
+// Unlike applyResource, it goes through the SDK's gRPC server, which is where
+// write-only values are left out of plans and state. prior is the state from
+// an earlier call, or cty.NilVal to create the resource. When the plan shows
+// no changes, prior is returned without applying.
+func applyThroughProtocol(t *testing.T, typeName string, prior cty.Value, config map[string]interface{}, meta interface{}) cty.Value {
+	t.Helper()
+	ctx := context.Background()
+
+	p := Provider()
+	p.SetMeta(meta)
+	server := schema.NewGRPCProviderServer(p)
+	block := p.ResourcesMap[typeName].CoreConfigSchema()
+	ty := block.ImpliedType()
+
+	b, err := json.Marshal(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	configVal, err := ctyjson.Unmarshal(b, ty)
+	if err != nil {
+		t.Fatalf("error converting config: %s", err)
+	}
+
+	// Terraform proposes the configuration, keeping prior values of computed
+	// attributes that aren't configured
+	proposed := configVal
+	if prior == cty.NilVal {
+		prior = cty.NullVal(ty)
+	} else {
+		attrs := configVal.AsValueMap()
+		for name, attr := range block.Attributes {
+			if attr.Computed && attrs[name].IsNull() {
+				attrs[name] = prior.GetAttr(name)
+			}
+		}
+		proposed = cty.ObjectVal(attrs)
+	}
+
+	encode := func(v cty.Value) *tfprotov5.DynamicValue {
+		b, err := msgpack.Marshal(v, ty)
+		if err != nil {
+			t.Fatalf("error encoding value: %s", err)
+		}
+		return &tfprotov5.DynamicValue{MsgPack: b}
+	}
+	decode := func(v *tfprotov5.DynamicValue) cty.Value {
+		val, err := msgpack.Unmarshal(v.MsgPack, ty)
+		if err != nil {
+			t.Fatalf("error decoding value: %s", err)
+		}
+		return val
+	}
+	checkDiagnostics := func(action string, diags []*tfprotov5.Diagnostic) {
+		for _, diag := range diags {
+			if diag.Severity == tfprotov5.DiagnosticSeverityError {
+				t.Fatalf("error %s: %s: %s", action, diag.Summary, diag.Detail)
+			}
+		}
+	}
+
+	planResp, err := server.PlanResourceChange(ctx, &tfprotov5.PlanResourceChangeRequest{
+		TypeName:         typeName,
+		PriorState:       encode(prior),
+		ProposedNewState: encode(proposed),
+		Config:           encode(configVal),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkDiagnostics("planning", planResp.Diagnostics)
+	if decode(planResp.PlannedState).RawEquals(prior) {
+		return prior
+	}
+
+	applyResp, err := server.ApplyResourceChange(ctx, &tfprotov5.ApplyResourceChangeRequest{
+		TypeName:       typeName,
+		PriorState:     encode(prior),
+		PlannedState:   planResp.PlannedState,
+		Config:         encode(configVal),
+		PlannedPrivate: planResp.PlannedPrivate,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkDiagnostics("applying", applyResp.Diagnostics)
+	return decode(applyResp.NewState)
+}
+
+// normalizeJSON round-trips v through JSON so that config values can be
+// compared with decoded request bodies.
+func normalizeJSON(t *testing.T, v interface{}) interface{} {
+	t.Helper()
+
+	b, err := json.Marshal(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var result interface{}
+	if err := json.Unmarshal(b, &result); err != nil {
+		t.Fatal(err)
+	}
+	return result
+}
+
+// captureLogs collects what the provider logs until the test ends.
+func captureLogs(t *testing.T) *bytes.Buffer {
+	t.Helper()
+
+	var buf bytes.Buffer
+	log.SetOutput(&buf)
+	t.Cleanup(func() { log.SetOutput(os.Stderr) })
+	return &buf
+}
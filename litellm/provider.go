@@ -1,9 +1,17 @@
 package litellm
 
 import (
+	"os"
+
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
 
+// Environment variables the provider settings default to.
+const (
+	envAPIBase = "LITELLM_API_BASE"
+	envAPIKey  = "LITELLM_API_KEY"
+)
+
 // Provider returns a terraform.ResourceProvider.
 func Provider() *schema.Provider {
 	return &schema.Provider{
@@ -13,20 +21,23 @@ func Provider() *schema.Provider {
 			"litellm_team_member":     resourceLiteLLMTeamMember(),
 			"litellm_team_member_add": resourceLiteLLMTeamMemberAdd(),
 			"litellm_key":             resourceKey(),
+			"litellm_team_models":     resourceLiteLLMTeamModels(),
+			"litellm_team_model":      resourceLiteLLMTeamModel(),
+			"litellm_team_callback":   resourceLiteLLMTeamCallback(),
 		},
 		Schema: map[string]*schema.Schema{
 			"api_base": {
 				Type:        schema.TypeString,
 				Required:    true,
 				Sensitive:   false,
-				DefaultFunc: schema.EnvDefaultFunc("LITELLM_API_BASE", nil),
+				DefaultFunc: schema.EnvDefaultFunc(envAPIBase, nil),
 				Description: "The base URL of the LiteLLM API",
 			},
 			"api_key": {
 				Type:        schema.TypeString,
 				Required:    true,
 				Sensitive:   true,
-				DefaultFunc: schema.EnvDefaultFunc("LITELLM_API_KEY", nil),
+				DefaultFunc: schema.EnvDefaultFunc(envAPIKey, nil),
 				Description: "The API key for authenticating with LiteLLM",
 			},
 		},
@@ -36,10 +47,26 @@ func Provider() *schema.Provider {
 
 // providerConfigure configures the provider with the given schema data.
 func providerConfigure(d *schema.ResourceData) (interface{}, error) {
+	config := resolveProviderConfig(d.Get("api_base").(string), d.Get("api_key").(string))
+
+	return NewClient(config.APIBase, config.APIKey), nil
+}
+
+// resolveProviderConfig returns the provider configuration, taking settings
+// that aren't configured from the environment. Both halves of the muxed
+// provider resolve their configuration through it, so they always talk to the
+// same proxy.
+func resolveProviderConfig(apiBase, apiKey string) ProviderConfig {
 	config := ProviderConfig{
-		APIBase: d.Get("api_base").(string),
-		APIKey:  d.Get("api_key").(string),
+		APIBase: apiBase,
+		APIKey:  apiKey,
+	}
+	if config.APIBase == "" {
+		config.APIBase = os.Getenv(envAPIBase)
+	}
+	if config.APIKey == "" {
+		config.APIKey = os.Getenv(envAPIKey)
 	}
 
-	return NewClient(config.APIBase, config.APIKey), nil
+	return config
 }
@@ -0,0 +1,83 @@
+package litellm
+
+import (
+	"context"
+	"os"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/ephemeral"
+	"github.com/hashicorp/terraform-plugin-framework/provider"
+	"github.com/hashicorp/terraform-plugin-framework/provider/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// frameworkProvider serves the parts of the provider that the plugin SDK cannot,
+// such as ephemeral resources. It is muxed together with Provider() in main.go,
+// so its provider schema must stay identical to the SDK one.
+type frameworkProvider struct{}
+
+type frameworkProviderModel struct {
+	APIBase types.String `tfsdk:"api_base"`
+	APIKey  types.String `tfsdk:"api_key"`
+}
+
+var _ provider.ProviderWithEphemeralResources = &frameworkProvider{}
+
+// NewFrameworkProvider returns the plugin framework half of the provider.
+func NewFrameworkProvider() provider.Provider {
+	return &frameworkProvider{}
+}
+
+func (p *frameworkProvider) Metadata(ctx context.Context, req provider.MetadataRequest, resp *provider.MetadataResponse) {
+	resp.TypeName = "litellm"
+}
+
+func (p *frameworkProvider) Schema(ctx context.Context, req provider.SchemaRequest, resp *provider.SchemaResponse) {
+	// The SDK reports a Required attribute with an environment default as
+	// Optional when the variable is set, so mirror that here.
+	apiBaseFromEnv := os.Getenv(envAPIBase) != ""
+	apiKeyFromEnv := os.Getenv(envAPIKey) != ""
+
+	resp.Schema = schema.Schema{
+		Attributes: map[string]schema.Attribute{
+			"api_base": schema.StringAttribute{
+				Required:    !apiBaseFromEnv,
+				Optional:    apiBaseFromEnv,
+				Description: "The base URL of the LiteLLM API",
+			},
+			"api_key": schema.StringAttribute{
+				Required:    !apiKeyFromEnv,
+				Optional:    apiKeyFromEnv,
+				Sensitive:   true,
+				Description: "The API key for authenticating with LiteLLM",
+			},
+		},
+	}
+}
+
+func (p *frameworkProvider) Configure(ctx context.Context, req provider.ConfigureRequest, resp *provider.ConfigureResponse) {
+	var data frameworkProviderModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	config := resolveProviderConfig(data.APIBase.ValueString(), data.APIKey.ValueString())
+	client := NewClient(config.APIBase, config.APIKey)
+	resp.EphemeralResourceData = client
+}
+
+func (p *frameworkProvider) Resources(ctx context.Context) []func() resource.Resource {
+	return nil
+}
+
+func (p *frameworkProvider) DataSources(ctx context.Context) []func() datasource.DataSource {
+	return nil
+}
+
+func (p *frameworkProvider) EphemeralResources(ctx context.Context) []func() ephemeral.EphemeralResource {
+	return []func() ephemeral.EphemeralResource{
+		newKeyEphemeralResource,
+	}
+}
@@ -0,0 +1,166 @@
+package litellm
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/providerserver"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/hashicorp/terraform-plugin-mux/tf5muxserver"
+)
+
+// muxedProvider serves both halves of the provider the way main.go does.
+func muxedProvider(t *testing.T) tfprotov5.ProviderServer {
+	t.Helper()
+
+	muxServer, err := tf5muxserver.NewMuxServer(context.Background(),
+		Provider().GRPCProvider,
+		providerserver.NewProtocol5(NewFrameworkProvider()),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return muxServer.ProviderServer()
+}
+
+// objectValue builds a value of the object type ty from attrs, leaving the
+// other attributes null.
+func objectValue(ty tftypes.Type, attrs map[string]tftypes.Value) tftypes.Value {
+	values := make(map[string]tftypes.Value)
+	for name, attrType := range ty.(tftypes.Object).AttributeTypes {
+		values[name] = tftypes.NewValue(attrType, nil)
+		if v, ok := attrs[name]; ok {
+			values[name] = v
+		}
+	}
+	return tftypes.NewValue(ty, values)
+}
+
+func dynamicValue(t *testing.T, ty tftypes.Type, attrs map[string]tftypes.Value) *tfprotov5.DynamicValue {
+	t.Helper()
+
+	v, err := tfprotov5.NewDynamicValue(ty, objectValue(ty, attrs))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &v
+}
+
+func assertNoDiagnostics(t *testing.T, action string, diags []*tfprotov5.Diagnostic) {
+	t.Helper()
+
+	for _, diag := range diags {
+		if diag.Severity == tfprotov5.DiagnosticSeverityError {
+			t.Fatalf("error %s: %s: %s", action, diag.Summary, diag.Detail)
+		}
+	}
+}
+
+func TestProviderSchemasMatch(t *testing.T) {
+	envs := map[string]map[string]string{
+		"no environment":    {envAPIBase: "", envAPIKey: ""},
+		"api_base from env": {envAPIBase: "http://localhost:4000", envAPIKey: ""},
+		"both from env":     {envAPIBase: "http://localhost:4000", envAPIKey: "sk-env"},
+	}
+	for name, env := range envs {
+		t.Run(name, func(t *testing.T) {
+			for k, v := range env {
+				t.Setenv(k, v)
+			}
+
+			resp, err := muxedProvider(t).GetProviderSchema(context.Background(), &tfprotov5.GetProviderSchemaRequest{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			assertNoDiagnostics(t, "getting the provider schema", resp.Diagnostics)
+		})
+	}
+}
+
+func TestResolveProviderConfig(t *testing.T) {
+	t.Setenv(envAPIBase, "http://env:4000")
+	t.Setenv(envAPIKey, "sk-env")
+
+	if config := resolveProviderConfig("", ""); config.APIBase != "http://env:4000" || config.APIKey != "sk-env" {
+		t.Errorf("expected settings from the environment, got %+v", config)
+	}
+	if config := resolveProviderConfig("http://config:4000", "sk-config"); config.APIBase != "http://config:4000" || config.APIKey != "sk-config" {
+		t.Errorf("expected configured settings to win over the environment, got %+v", config)
+	}
+}
+
+func TestEphemeralKeyOpenClose(t *testing.T) {
+	proxy := newFakeProxy(t)
+	ctx := context.Background()
+	server := muxedProvider(t)
+
+	schemas, err := server.GetProviderSchema(ctx, &tfprotov5.GetProviderSchemaRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertNoDiagnostics(t, "getting the provider schema", schemas.Diagnostics)
+
+	configureResp, err := server.ConfigureProvider(ctx, &tfprotov5.ConfigureProviderRequest{
+		Config: dynamicValue(t, schemas.Provider.ValueType(), map[string]tftypes.Value{
+			"api_base": tftypes.NewValue(tftypes.String, proxy.server.URL),
+			"api_key":  tftypes.NewValue(tftypes.String, "sk-master"),
+		}),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertNoDiagnostics(t, "configuring the provider", configureResp.Diagnostics)
+
+	keyType := schemas.EphemeralResourceSchemas["litellm_key"].ValueType()
+	openResp, err := server.OpenEphemeralResource(ctx, &tfprotov5.OpenEphemeralResourceRequest{
+		TypeName: "litellm_key",
+		Config: dynamicValue(t, keyType, map[string]tftypes.Value{
+			"key_alias": tftypes.NewValue(tftypes.String, "ci-run"),
+		}),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertNoDiagnostics(t, "opening the key", openResp.Diagnostics)
+
+	result, err := openResp.Result.Unmarshal(keyType)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var attrs map[string]tftypes.Value
+	if err := result.As(&attrs); err != nil {
+		t.Fatal(err)
+	}
+	var key string
+	if err := attrs["key"].As(&key); err != nil || key == "" {
+		t.Fatalf("expected the generated key in the result, got %v", attrs["key"])
+	}
+	if !attrs["duration"].IsNull() {
+		t.Errorf("expected the unset duration to stay null in the result, got %v", attrs["duration"])
+	}
+	if strings.Contains(string(openResp.Private), key) {
+		t.Error("expected the private data not to contain the plaintext key")
+	}
+
+	record := proxy.keys[hashToken(key)]
+	if record == nil {
+		t.Fatal("expected the key to be generated on the proxy")
+	}
+	if record["key_alias"] != "ci-run" || record["expires"] == nil {
+		t.Errorf("expected the key to have its alias and the default expiry, got %v", record)
+	}
+
+	closeResp, err := server.CloseEphemeralResource(ctx, &tfprotov5.CloseEphemeralResourceRequest{
+		TypeName: "litellm_key",
+		Private:  openResp.Private,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertNoDiagnostics(t, "closing the key", closeResp.Diagnostics)
+	if len(proxy.keys) != 0 {
+		t.Errorf("expected the key to be deleted on close, got %v", proxy.keys)
+	}
+}
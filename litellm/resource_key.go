@@ -2,10 +2,18 @@ package litellm
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"log"
+	"reflect"
+	"regexp"
+	"strings"
+	"time"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/structure"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 )
 
 func resourceKey() *schema.Resource {
@@ -14,8 +22,53 @@ func resourceKey() *schema.Resource {
 		ReadContext:   resourceKeyRead,
 		UpdateContext: resourceKeyUpdate,
 		DeleteContext: resourceKeyDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceKeyImport,
+		},
+		CustomizeDiff: resourceKeyCustomizeDiff,
+		SchemaVersion: 1,
+		StateUpgraders: []schema.StateUpgrader{
+			{
+				Version: 0,
+				Type:    resourceKeyV0().CoreConfigSchema().ImpliedType(),
+				Upgrade: resourceKeyStateUpgradeV0,
+			},
+		},
 		Schema: map[string]*schema.Schema{
 			"key": {
+				Type:      schema.TypeString,
+				Computed:  true,
+				Sensitive: true,
+			},
+			"key_wo": {
+				Type:      schema.TypeString,
+				Optional:  true,
+				Sensitive: true,
+				WriteOnly: true,
+			},
+			"key_wo_version": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				ForceNew:     true,
+				RequiredWith: []string{"key_wo"},
+			},
+			"pgp_key": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				ForceNew:      true,
+				ConflictsWith: []string{"key_wo"},
+			},
+			"pgp_keyring": {
+				Type:     schema.TypeMap,
+				Optional: true,
+				ForceNew: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+			"encrypted_key": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"key_fingerprint": {
 				Type:     schema.TypeString,
 				Computed: true,
 			},
@@ -29,8 +82,17 @@ func resourceKey() *schema.Resource {
 				Optional: true,
 			},
 			"user_id": {
-				Type:     schema.TypeString,
-				Optional: true,
+				Type:          schema.TypeString,
+				Optional:      true,
+				ForceNew:      true,
+				ConflictsWith: []string{"service_account_id"},
+			},
+			"service_account_id": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				ForceNew:      true,
+				RequiredWith:  []string{"team_id"},
+				ConflictsWith: []string{"user_id"},
 			},
 			"team_id": {
 				Type:     schema.TypeString,
@@ -41,9 +103,17 @@ func resourceKey() *schema.Resource {
 				Optional: true,
 			},
 			"metadata": {
-				Type:     schema.TypeMap,
-				Optional: true,
-				Elem:     &schema.Schema{Type: schema.TypeString},
+				Type:          schema.TypeMap,
+				Optional:      true,
+				Elem:          &schema.Schema{Type: schema.TypeString},
+				ConflictsWith: []string{"metadata_json"},
+			},
+			"metadata_json": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				ValidateFunc:     validateJSONObject,
+				DiffSuppressFunc: structure.SuppressJsonDiff,
+				ConflictsWith:    []string{"metadata"},
 			},
 			"tpm_limit": {
 				Type:     schema.TypeInt,
@@ -90,10 +160,12 @@ func resourceKey() *schema.Resource {
 				Elem:     &schema.Schema{Type: schema.TypeString},
 			},
 			"model_max_budget": {
-				Type:     schema.TypeMap,
-				Optional: true,
-				Elem:     &schema.Schema{Type: schema.TypeFloat},
+				Type:          schema.TypeMap,
+				Optional:      true,
+				Elem:          &schema.Schema{Type: schema.TypeFloat},
+				ConflictsWith: []string{"model_budget"},
 			},
+			"model_budget": modelBudgetSchema("model_max_budget"),
 			"model_rpm_limit": {
 				Type:     schema.TypeMap,
 				Optional: true,
@@ -118,14 +190,114 @@ func resourceKey() *schema.Resource {
 				Optional: true,
 				Elem:     &schema.Schema{Type: schema.TypeString},
 			},
+			"key_type": {
+				Type:     schema.TypeString,
+				Optional: true,
+				ForceNew: true,
+				ValidateFunc: validation.StringInSlice([]string{
+					"llm_api",
+					"management",
+					"read_only",
+					"default",
+				}, false),
+				ConflictsWith:    []string{"allowed_routes"},
+				DiffSuppressFunc: suppressMatchingKeyType,
+			},
+			"allowed_routes": {
+				Type:     schema.TypeList,
+				Optional: true,
+				Computed: true,
+				Elem: &schema.Schema{
+					Type:         schema.TypeString,
+					ValidateFunc: validation.StringMatch(allowedRoutePattern, "must be a path such as \"/chat/completions\" or a route group such as \"llm_api_routes\""),
+				},
+				ConflictsWith: []string{"key_type"},
+			},
+			"enforced_params": {
+				Type:     schema.TypeList,
+				Optional: true,
+				Elem: &schema.Schema{
+					Type:         schema.TypeString,
+					ValidateFunc: validation.StringMatch(enforcedParamPattern, "must be a request parameter such as \"user\" or \"metadata.generation_name\""),
+				},
+			},
 			"spend": {
 				Type:     schema.TypeFloat,
 				Computed: true,
 			},
+			// Only sent when the key is created, so later changes are
+			// ignored rather than replacing a key that is in use
 			"send_invite_email": {
+				Type:             schema.TypeBool,
+				Optional:         true,
+				DiffSuppressFunc: suppressAfterCreate,
+			},
+			"rotation_triggers": {
+				Type:          schema.TypeMap,
+				Optional:      true,
+				Elem:          &schema.Schema{Type: schema.TypeString},
+				ConflictsWith: []string{"key_wo"},
+			},
+			"rotation_period": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				ValidateFunc:  validation.StringMatch(liteLLMDurationPattern, "must be a LiteLLM duration such as \"30d\" or \"1mo\""),
+				ConflictsWith: []string{"key_wo"},
+			},
+			"last_rotated_at": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"auto_rotate": {
+				Type:         schema.TypeBool,
+				Optional:     true,
+				RequiredWith: []string{"key_alias"},
+			},
+			"rotation_interval": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validation.StringMatch(liteLLMDurationPattern, "must be a LiteLLM duration such as \"30d\" or \"1mo\""),
+				RequiredWith: []string{"auto_rotate"},
+			},
+			"expires_at": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"is_expired": {
+				Type:     schema.TypeBool,
+				Computed: true,
+			},
+			"replace_when_expired": {
 				Type:     schema.TypeBool,
 				Optional: true,
 			},
+			"renew_before": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validation.StringMatch(liteLLMDurationPattern, "must be a LiteLLM duration such as \"1d\" or \"2w\""),
+				RequiredWith: []string{"replace_when_expired"},
+			},
+			"temp_budget_increase": {
+				Type:             schema.TypeFloat,
+				Optional:         true,
+				RequiredWith:     []string{"temp_budget_expiry"},
+				DiffSuppressFunc: suppressExpiredTempBudget,
+			},
+			"temp_budget_expiry": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				ValidateFunc:     validation.IsRFC3339Time,
+				RequiredWith:     []string{"temp_budget_increase"},
+				DiffSuppressFunc: suppressTempBudgetExpiryDiff,
+			},
+			"effective_max_budget": {
+				Type:     schema.TypeFloat,
+				Computed: true,
+			},
+			"temp_budget_expires_at": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
 		},
 	}
 }
@@ -133,7 +305,14 @@ func resourceKey() *schema.Resource {
 func resourceKeyCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	c := m.(*Client)
 
-	key := &Key{}
+	// Resolve the PGP key before creating anything, so that a bad key
+	// doesn't leave an unmanaged key behind.
+	publicKey, err := keyPGPPublicKey(d)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	key := &Key{Key: getWriteOnlyString(d, "key")}
 	mapResourceDataToKey(d, key)
 
 	createdKey, err := c.CreateKey(key)
@@ -141,7 +320,26 @@ func resourceKeyCreate(ctx context.Context, d *schema.ResourceData, m interface{
 		return diag.FromErr(fmt.Errorf("error creating key: %s", err))
 	}
 
-	d.SetId(createdKey.Key)
+	// The hashed token identifies the key so that the secret never shows up in
+	// the resource ID. A key supplied through key_wo must not reach the state at all.
+	d.SetId(GetStringValue(createdKey.Token, hashToken(createdKey.Key)))
+	d.Set("last_rotated_at", time.Now().UTC().Format(time.RFC3339))
+	if key.Key == "" {
+		if err := setKeySecret(d, publicKey, createdKey.Key); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	// /key/generate doesn't accept temporary budget increases
+	if key.TempBudgetIncrease != nil {
+		update := *key
+		update.Key = d.Id()
+		update.Duration = ""
+		if _, err := c.UpdateKey(&update); err != nil {
+			return diag.FromErr(fmt.Errorf("error setting temporary budget increase: %s", err))
+		}
+	}
+
 	return resourceKeyRead(ctx, d, m)
 }
 
@@ -149,7 +347,25 @@ func resourceKeyRead(ctx context.Context, d *schema.ResourceData, m interface{})
 	c := m.(*Client)
 
 	key, err := c.GetKey(d.Id())
+	if err != nil && isKeyNotFoundError(err) && d.Get("auto_rotate").(bool) {
+		// The proxy rotated the key itself, which changes its hashed token. If
+		// no key has the alias either, the key was deleted.
+		rotatedKey, aliasErr := c.GetKeyByAlias(d.Get("key_alias").(string))
+		switch {
+		case aliasErr == nil:
+			log.Printf("[INFO] Key %s was rotated by the proxy, new token is %s", d.Id(), rotatedKey.Token)
+			d.SetId(rotatedKey.Token)
+			key, err = rotatedKey, nil
+		case !errors.Is(aliasErr, errKeyAliasNotFound):
+			return diag.FromErr(fmt.Errorf("error reading key: %s", aliasErr))
+		}
+	}
 	if err != nil {
+		if isKeyNotFoundError(err) {
+			log.Printf("[WARN] Key %s not found, removing from state", d.Id())
+			d.SetId("")
+			return nil
+		}
 		return diag.FromErr(fmt.Errorf("error reading key: %s", err))
 	}
 
@@ -158,18 +374,305 @@ func resourceKeyRead(ctx context.Context, d *schema.ResourceData, m interface{})
 		return nil
 	}
 
-	mapKeyToResourceData(d, key)
+	if err := mapKeyToResourceData(d, key); err != nil {
+		return diag.FromErr(err)
+	}
+
+	expired, err := keyRenewalDue(key.Expires, "", time.Now())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	d.Set("expires_at", key.Expires)
+	d.Set("is_expired", expired)
+
+	if key.LastRotationAt != "" {
+		d.Set("last_rotated_at", key.LastRotationAt)
+	} else if d.Get("last_rotated_at").(string) == "" && key.CreatedAt != "" {
+		d.Set("last_rotated_at", key.CreatedAt)
+	}
+
 	return nil
 }
 
+// resourceKeyCustomizeDiff validates model_budget and pgp_key at plan time. It
+// plans a replacement when replace_when_expired is set and the key is within
+// renew_before of its expiry, and an in-place rotation when rotation_triggers
+// change or the key is older than rotation_period.
+func resourceKeyCustomizeDiff(ctx context.Context, d *schema.ResourceDiff, m interface{}) error {
+	if err := validateModelBudgets(d.Get("model_budget").(*schema.Set).List()); err != nil {
+		return err
+	}
+
+	if pgpKey := d.Get("pgp_key").(string); pgpKey != "" && d.NewValueKnown("pgp_key") && d.NewValueKnown("pgp_keyring") {
+		publicKey, err := retrievePGPKey(pgpKey, d.Get("pgp_keyring").(map[string]interface{}))
+		if err != nil {
+			return err
+		}
+		if _, err := readPGPEntity(publicKey); err != nil {
+			return fmt.Errorf("pgp_key: %s", err)
+		}
+	}
+
+	if d.Id() == "" {
+		return nil
+	}
+
+	if d.Get("replace_when_expired").(bool) {
+		renew, err := keyRenewalDue(d.Get("expires_at").(string), d.Get("renew_before").(string), time.Now())
+		if err != nil {
+			return err
+		}
+		if renew {
+			if err := d.SetNewComputed("expires_at"); err != nil {
+				return err
+			}
+			return d.ForceNew("expires_at")
+		}
+	}
+
+	tempBudgetChanged := d.HasChanges("temp_budget_increase", "temp_budget_expiry") && !tempBudgetChangeExpired(d.GetChange("temp_budget_expiry"))
+	if d.HasChange("max_budget") || tempBudgetChanged {
+		for _, attr := range []string{"effective_max_budget", "temp_budget_expires_at"} {
+			if err := d.SetNewComputed(attr); err != nil {
+				return err
+			}
+		}
+	}
+
+	// allowed_routes is computed from key_type, so removing it from the
+	// configuration of a key without a key_type is planned as clearing it
+	rawConfig := d.GetRawConfig()
+	if !rawConfig.IsNull() && rawConfig.GetAttr("key_type").IsNull() && rawConfig.GetAttr("allowed_routes").IsNull() && len(d.Get("allowed_routes").([]interface{})) > 0 {
+		if err := d.SetNew("allowed_routes", []interface{}{}); err != nil {
+			return err
+		}
+	}
+
+	// Changing duration restarts the key's expiry
+	if d.HasChange("duration") {
+		for _, attr := range []string{"expires_at", "is_expired"} {
+			if err := d.SetNewComputed(attr); err != nil {
+				return err
+			}
+		}
+	}
+
+	due, err := keyRotationDue(d.Get("last_rotated_at").(string), d.Get("rotation_period").(string), time.Now())
+	if err != nil {
+		return err
+	}
+
+	if d.HasChange("rotation_triggers") || due {
+		attrs := []string{"key", "last_rotated_at"}
+		if d.Get("pgp_key").(string) != "" {
+			attrs = []string{"encrypted_key", "key_fingerprint", "last_rotated_at"}
+		}
+		for _, attr := range attrs {
+			if err := d.SetNewComputed(attr); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// keyRotationDue reports whether a key last rotated at lastRotatedAt is older
+// than period at now.
+func keyRotationDue(lastRotatedAt, period string, now time.Time) (bool, error) {
+	if lastRotatedAt == "" || period == "" {
+		return false, nil
+	}
+
+	rotatedAt, err := parseTimestamp(lastRotatedAt)
+	if err != nil {
+		return false, err
+	}
+
+	rotateAt, err := addLiteLLMDuration(rotatedAt, period)
+	if err != nil {
+		return false, err
+	}
+
+	return !now.Before(rotateAt), nil
+}
+
+// keyRenewalDue reports whether a key expiring at expiresAt has expired, or
+// will expire within renewBefore, at now. Keys without an expiry never do.
+func keyRenewalDue(expiresAt, renewBefore string, now time.Time) (bool, error) {
+	if expiresAt == "" {
+		return false, nil
+	}
+
+	expires, err := parseTimestamp(expiresAt)
+	if err != nil {
+		return false, err
+	}
+
+	if renewBefore != "" {
+		now, err = addLiteLLMDuration(now, renewBefore)
+		if err != nil {
+			return false, err
+		}
+	}
+
+	return !now.Before(expires), nil
+}
+
+// keyTypeRoutes are the routes LiteLLM allows for each key_type. The proxy
+// only stores the routes, not the key_type.
+var keyTypeRoutes = map[string][]string{
+	"llm_api":    {"llm_api_routes"},
+	"management": {"management_routes"},
+	"read_only":  {"info_routes"},
+	"default":    {},
+}
+
+// suppressMatchingKeyType ignores setting key_type on an existing key whose
+// allowed routes already match it, such as an imported key, instead of
+// replacing the key.
+func suppressMatchingKeyType(k, old, new string, d *schema.ResourceData) bool {
+	routes, ok := keyTypeRoutes[new]
+	if d.Id() == "" || old != "" || !ok {
+		return false
+	}
+	return reflect.DeepEqual(expandStringList(d.Get("allowed_routes").([]interface{})), routes)
+}
+
+// suppressAfterCreate ignores changes to arguments that only apply when the
+// key is created.
+func suppressAfterCreate(k, old, new string, d *schema.ResourceData) bool {
+	return d.Id() != ""
+}
+
+// tempBudgetExpired reports whether a temporary budget increase expiring at
+// expiry no longer applies at now. Unparseable timestamps are treated as not
+// expired, so that they still show up in diffs.
+func tempBudgetExpired(expiry string, now time.Time) bool {
+	t, err := parseTimestamp(expiry)
+	if err != nil {
+		return false
+	}
+	return !now.Before(t)
+}
+
+// tempBudgetChangeExpired reports whether the temporary budget increase being
+// planned, or the one being removed, has already expired.
+func tempBudgetChangeExpired(o, n interface{}) bool {
+	expiry := n.(string)
+	if expiry == "" {
+		expiry = o.(string)
+	}
+	return expiry != "" && tempBudgetExpired(expiry, time.Now())
+}
+
+// suppressExpiredTempBudget ignores changes to a temporary budget increase
+// once it has expired, since the proxy no longer applies it.
+func suppressExpiredTempBudget(k, old, new string, d *schema.ResourceData) bool {
+	return tempBudgetChangeExpired(d.GetChange("temp_budget_expiry"))
+}
+
+// suppressTempBudgetExpiryDiff ignores expired temporary budget increases and
+// differences in how the same instant is formatted.
+func suppressTempBudgetExpiryDiff(k, old, new string, d *schema.ResourceData) bool {
+	if tempBudgetChangeExpired(d.GetChange("temp_budget_expiry")) {
+		return true
+	}
+
+	oldTime, err := parseTimestamp(old)
+	if err != nil {
+		return false
+	}
+	newTime, err := parseTimestamp(new)
+	if err != nil {
+		return false
+	}
+	return oldTime.Equal(newTime)
+}
+
+// allowedRoutePattern matches a route path or a LiteLLM route group.
+var allowedRoutePattern = regexp.MustCompile(`^(/\S*|[a-z_]+_routes)$`)
+
+// enforcedParamPattern matches a request parameter, optionally nested with dots.
+var enforcedParamPattern = regexp.MustCompile(`^[A-Za-z0-9_]+(\.[A-Za-z0-9_]+)*$`)
+
+// hashedTokenPattern matches the sha256 hashed token LiteLLM stores for a key.
+var hashedTokenPattern = regexp.MustCompile(`^[0-9a-f]{64}$`)
+
+// resourceKeyImport accepts a plaintext key, a hashed token or a key_alias.
+func resourceKeyImport(ctx context.Context, d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+	c := m.(*Client)
+	id := d.Id()
+
+	switch {
+	case strings.HasPrefix(id, "sk-"):
+		d.Set("key", id)
+		d.SetId(hashToken(id))
+	case hashedTokenPattern.MatchString(id):
+		// The hashed token works everywhere the plaintext key does
+	default:
+		key, err := c.GetKeyByAlias(id)
+		if err != nil {
+			return nil, fmt.Errorf("error importing key: %s", err)
+		}
+		if key.Token == "" {
+			return nil, fmt.Errorf("error importing key: key list response for key_alias %q did not include a token", id)
+		}
+		d.SetId(key.Token)
+	}
+
+	return []*schema.ResourceData{d}, nil
+}
+
+// resourceKeyStateUpgradeV0 replaces the plaintext key that version 0 used as
+// the resource ID with its hashed token.
+func resourceKeyStateUpgradeV0(ctx context.Context, rawState map[string]interface{}, meta interface{}) (map[string]interface{}, error) {
+	id, _ := rawState["id"].(string)
+	if !strings.HasPrefix(id, "sk-") {
+		return rawState, nil
+	}
+
+	if key, _ := rawState["key"].(string); key == "" {
+		rawState["key"] = id
+	}
+	rawState["id"] = hashToken(id)
+
+	return rawState, nil
+}
+
 func resourceKeyUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	c := m.(*Client)
 
+	// Rotations are decided when planning, which marks last_rotated_at as
+	// unknown, so that a key that becomes due after the plan isn't rotated
+	if d.HasChange("last_rotated_at") {
+		publicKey, err := keyPGPPublicKey(d)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+
+		rotatedKey, err := c.RegenerateKey(d.Id())
+		if err != nil {
+			return diag.FromErr(fmt.Errorf("error rotating key: %s", err))
+		}
+
+		d.SetId(GetStringValue(rotatedKey.Token, hashToken(rotatedKey.Key)))
+		d.Set("last_rotated_at", time.Now().UTC().Format(time.RFC3339))
+		if err := setKeySecret(d, publicKey, rotatedKey.Key); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
 	key := &Key{Key: d.Id()}
 	mapResourceDataToKey(d, key)
 
-	_, err := c.UpdateKey(key)
-	if err != nil {
+	// Sending duration again would restart the key's expiry
+	if !d.HasChange("duration") {
+		key.Duration = ""
+	}
+	key.UpdateRotation = d.HasChanges("auto_rotate", "rotation_interval")
+
+	if _, err := c.UpdateKey(key); err != nil {
 		return diag.FromErr(fmt.Errorf("error updating key: %s", err))
 	}
 
@@ -188,101 +691,132 @@ func resourceKeyDelete(ctx context.Context, d *schema.ResourceData, m interface{
 	return nil
 }
 
+// keyPGPPublicKey returns the base64-encoded public key that generated keys are
+// encrypted with, or "" if pgp_key is not set.
+func keyPGPPublicKey(d *schema.ResourceData) (string, error) {
+	pgpKey := d.Get("pgp_key").(string)
+	if pgpKey == "" {
+		return "", nil
+	}
+	return retrievePGPKey(pgpKey, d.Get("pgp_keyring").(map[string]interface{}))
+}
+
+// setKeySecret stores a newly generated key in state. When pgp_key is set only
+// the encrypted key and the PGP key's fingerprint are stored.
+func setKeySecret(d *schema.ResourceData, publicKey, secret string) error {
+	if publicKey == "" {
+		d.Set("key", secret)
+		return nil
+	}
+
+	fingerprint, encrypted, err := encryptValue(publicKey, secret)
+	if err != nil {
+		return fmt.Errorf("error encrypting key: %s", err)
+	}
+	d.Set("key_fingerprint", fingerprint)
+	d.Set("encrypted_key", encrypted)
+	return nil
+}
+
 func mapResourceDataToKey(d *schema.ResourceData, key *Key) {
 	key.Models = expandStringList(d.Get("models").([]interface{}))
-	key.MaxBudget = d.Get("max_budget").(float64)
+	key.MaxBudget = getOptionalFloat(d, "max_budget")
 	key.UserID = d.Get("user_id").(string)
+	key.ServiceAccountID = d.Get("service_account_id").(string)
 	key.TeamID = d.Get("team_id").(string)
-	key.MaxParallelRequests = d.Get("max_parallel_requests").(int)
-	key.Metadata = d.Get("metadata").(map[string]interface{})
-	key.TPMLimit = d.Get("tpm_limit").(int)
-	key.RPMLimit = d.Get("rpm_limit").(int)
+	key.MaxParallelRequests = getOptionalInt(d, "max_parallel_requests")
+	key.Metadata = getMetadata(d)
+	key.TPMLimit = getOptionalInt(d, "tpm_limit")
+	key.RPMLimit = getOptionalInt(d, "rpm_limit")
 	key.BudgetDuration = d.Get("budget_duration").(string)
 	key.AllowedCacheControls = expandStringList(d.Get("allowed_cache_controls").([]interface{}))
-	key.SoftBudget = d.Get("soft_budget").(float64)
+	key.SoftBudget = getOptionalFloat(d, "soft_budget")
 	key.KeyAlias = d.Get("key_alias").(string)
 	key.Duration = d.Get("duration").(string)
 	key.Aliases = d.Get("aliases").(map[string]interface{})
 	key.Config = d.Get("config").(map[string]interface{})
 	key.Permissions = d.Get("permissions").(map[string]interface{})
-	key.ModelMaxBudget = d.Get("model_max_budget").(map[string]interface{})
+	if budgets := d.Get("model_budget").(*schema.Set); budgets.Len() > 0 {
+		key.ModelMaxBudget = expandModelBudgets(budgets.List())
+	} else {
+		key.ModelMaxBudget = d.Get("model_max_budget").(map[string]interface{})
+	}
 	key.ModelRPMLimit = d.Get("model_rpm_limit").(map[string]interface{})
 	key.ModelTPMLimit = d.Get("model_tpm_limit").(map[string]interface{})
 	key.Guardrails = expandStringList(d.Get("guardrails").([]interface{}))
 	key.Blocked = d.Get("blocked").(bool)
 	key.Tags = expandStringList(d.Get("tags").([]interface{}))
+	key.KeyType = d.Get("key_type").(string)
+	key.AllowedRoutes = expandStringList(d.Get("allowed_routes").([]interface{}))
+	key.EnforcedParams = expandStringList(d.Get("enforced_params").([]interface{}))
 	key.SendInviteEmail = d.Get("send_invite_email").(bool)
-}
-
-func mapKeyToResourceData(d *schema.ResourceData, key *Key) {
-	d.Set("key", key.Key)
+	key.AutoRotate = d.Get("auto_rotate").(bool)
+	key.RotationInterval = d.Get("rotation_interval").(string)
 
-	if len(key.Models) > 0 {
-		d.Set("models", key.Models)
-	}
-	if key.MaxBudget != 0 {
-		d.Set("max_budget", key.MaxBudget)
-	}
-	if key.UserID != "" {
-		d.Set("user_id", key.UserID)
-	}
-	if key.TeamID != "" {
-		d.Set("team_id", key.TeamID)
-	}
-	if key.MaxParallelRequests != 0 {
-		d.Set("max_parallel_requests", key.MaxParallelRequests)
-	}
-	if key.Metadata != nil {
-		d.Set("metadata", key.Metadata)
-	}
-	if key.TPMLimit != 0 {
-		d.Set("tpm_limit", key.TPMLimit)
-	}
-	if key.RPMLimit != 0 {
-		d.Set("rpm_limit", key.RPMLimit)
-	}
-	if key.BudgetDuration != "" {
-		d.Set("budget_duration", key.BudgetDuration)
-	}
-	if len(key.AllowedCacheControls) > 0 {
-		d.Set("allowed_cache_controls", key.AllowedCacheControls)
-	}
-	if key.SoftBudget != 0 {
-		d.Set("soft_budget", key.SoftBudget)
+	// An expired temporary budget increase no longer applies, so it is not
+	// sent again. One that is removed while it still applies is cleared.
+	if expiry := d.Get("temp_budget_expiry").(string); expiry != "" && !tempBudgetExpired(expiry, time.Now()) {
+		key.TempBudgetIncrease = getOptionalFloat(d, "temp_budget_increase")
+		key.TempBudgetExpiry = expiry
+	} else if o, _ := d.GetChange("temp_budget_expiry"); o.(string) != "" && !tempBudgetExpired(o.(string), time.Now()) {
+		key.ClearTempBudget = true
 	}
-	if key.KeyAlias != "" {
-		d.Set("key_alias", key.KeyAlias)
+}
+
+// mapKeyToResourceData sets every attribute the proxy reports, including
+// cleared ones, so that values removed outside of Terraform show up as drift.
+// duration is not returned by the proxy and is kept from the configuration.
+func mapKeyToResourceData(d *schema.ResourceData, key *Key) error {
+	d.Set("models", key.Models)
+	d.Set("max_budget", key.MaxBudget)
+	d.Set("user_id", key.UserID)
+	d.Set("service_account_id", key.ServiceAccountID)
+	d.Set("team_id", key.TeamID)
+	d.Set("max_parallel_requests", key.MaxParallelRequests)
+	if err := setMetadata(d, key.Metadata); err != nil {
+		return err
 	}
+	d.Set("tpm_limit", key.TPMLimit)
+	d.Set("rpm_limit", key.RPMLimit)
+	d.Set("budget_duration", key.BudgetDuration)
+	d.Set("allowed_cache_controls", key.AllowedCacheControls)
+	d.Set("soft_budget", key.SoftBudget)
+	d.Set("key_alias", key.KeyAlias)
 	if key.Duration != "" {
 		d.Set("duration", key.Duration)
 	}
-	if key.Aliases != nil {
-		d.Set("aliases", key.Aliases)
-	}
-	if key.Config != nil {
-		d.Set("config", key.Config)
-	}
-	if key.Permissions != nil {
-		d.Set("permissions", key.Permissions)
-	}
-	if key.ModelMaxBudget != nil {
-		d.Set("model_max_budget", key.ModelMaxBudget)
-	}
-	if key.ModelRPMLimit != nil {
-		d.Set("model_rpm_limit", key.ModelRPMLimit)
-	}
-	if key.ModelTPMLimit != nil {
-		d.Set("model_tpm_limit", key.ModelTPMLimit)
-	}
-	if len(key.Guardrails) > 0 {
-		d.Set("guardrails", key.Guardrails)
-	}
+	d.Set("aliases", key.Aliases)
+	d.Set("config", key.Config)
+	d.Set("permissions", key.Permissions)
+	maxBudgets, budgets := flattenModelMaxBudget(key.ModelMaxBudget)
+	d.Set("model_max_budget", maxBudgets)
+	d.Set("model_budget", budgets)
+	d.Set("model_rpm_limit", key.ModelRPMLimit)
+	d.Set("model_tpm_limit", key.ModelTPMLimit)
+	d.Set("guardrails", key.Guardrails)
 	d.Set("blocked", key.Blocked)
-	if len(key.Tags) > 0 {
-		d.Set("tags", key.Tags)
-	}
-	if key.Spend != 0 {
-		d.Set("spend", key.Spend)
+	d.Set("tags", key.Tags)
+	d.Set("allowed_routes", key.AllowedRoutes)
+	d.Set("enforced_params", key.EnforcedParams)
+	d.Set("spend", key.Spend)
+	d.Set("auto_rotate", key.AutoRotate)
+	d.Set("rotation_interval", key.RotationInterval)
+	d.Set("temp_budget_increase", key.TempBudgetIncrease)
+	d.Set("temp_budget_expiry", key.TempBudgetExpiry)
+
+	// The increase only counts towards the budget until it expires
+	effectiveMaxBudget := key.MaxBudget
+	tempBudgetExpiresAt := ""
+	if key.TempBudgetIncrease != nil && key.TempBudgetExpiry != "" && !tempBudgetExpired(key.TempBudgetExpiry, time.Now()) {
+		if expiry, err := parseTimestamp(key.TempBudgetExpiry); err == nil {
+			tempBudgetExpiresAt = expiry.Format(time.RFC3339)
+		}
+		if effectiveMaxBudget != nil {
+			budget := *effectiveMaxBudget + *key.TempBudgetIncrease
+			effectiveMaxBudget = &budget
+		}
 	}
-	d.Set("send_invite_email", key.SendInviteEmail)
+	d.Set("effective_max_budget", effectiveMaxBudget)
+	d.Set("temp_budget_expires_at", tempBudgetExpiresAt)
+	return nil
 }
@@ -4,6 +4,14 @@ import (
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
 
+// resourceKeyV0 is the litellm_key resource as of schema version 0, when the
+// plaintext key was used as the resource ID. It is only used to upgrade state.
+func resourceKeyV0() *schema.Resource {
+	return &schema.Resource{
+		Schema: resourceKeySchema(),
+	}
+}
+
 func resourceKeySchema() map[string]*schema.Schema {
 	return map[string]*schema.Schema{
 		"key": {
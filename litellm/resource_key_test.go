@@ -0,0 +1,841 @@
+package litellm
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/hex"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ProtonMail/go-crypto/openpgp"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func testKeyConfig() map[string]interface{} {
+	return map[string]interface{}{
+		"models":                 []interface{}{"gpt-4"},
+		"max_budget":             100.0,
+		"user_id":                "user-1",
+		"team_id":                "team-1",
+		"max_parallel_requests":  5,
+		"metadata":               map[string]interface{}{"environment": "production"},
+		"tpm_limit":              1000,
+		"rpm_limit":              60,
+		"budget_duration":        "30d",
+		"allowed_cache_controls": []interface{}{"no-cache"},
+		"soft_budget":            80.0,
+		"key_alias":              "key-1",
+		"duration":               "30d",
+		"aliases":                map[string]interface{}{"gpt-4": "gpt-4o"},
+		"config":                 map[string]interface{}{"default_model": "gpt-4"},
+		"permissions":            map[string]interface{}{"can_create_keys": "true"},
+		"model_max_budget":       map[string]interface{}{"gpt-4": 50.0},
+		"model_rpm_limit":        map[string]interface{}{"gpt-4": 30},
+		"model_tpm_limit":        map[string]interface{}{"gpt-4": 500},
+		"guardrails":             []interface{}{"pii"},
+		"blocked":                false,
+		"tags":                   []interface{}{"production"},
+		"send_invite_email":      true,
+	}
+}
+
+// updatedKeyConfig changes every attribute that /key/update can change in place.
+func updatedKeyConfig() map[string]interface{} {
+	return mergeMaps(testKeyConfig(), map[string]interface{}{
+		"models":                 []interface{}{"gpt-4", "claude-3"},
+		"max_budget":             200.0,
+		"team_id":                "team-2",
+		"max_parallel_requests":  10,
+		"metadata":               map[string]interface{}{"environment": "staging"},
+		"tpm_limit":              2000,
+		"rpm_limit":              120,
+		"budget_duration":        "7d",
+		"allowed_cache_controls": []interface{}{"no-cache", "no-store"},
+		"soft_budget":            90.0,
+		"key_alias":              "key-2",
+		"duration":               "60d",
+		"aliases":                map[string]interface{}{"gpt-4": "gpt-4-turbo"},
+		"config":                 map[string]interface{}{"default_model": "claude-3"},
+		"permissions":            map[string]interface{}{"can_create_keys": "false"},
+		"model_max_budget":       map[string]interface{}{"gpt-4": 75.0},
+		"model_rpm_limit":        map[string]interface{}{"gpt-4": 40},
+		"model_tpm_limit":        map[string]interface{}{"gpt-4": 800},
+		"guardrails":             []interface{}{"pii", "toxicity"},
+		"blocked":                true,
+		"tags":                   []interface{}{"staging"},
+	})
+}
+
+func TestResourceKeyAttributesRoundTrip(t *testing.T) {
+	proxy := newFakeProxy(t)
+	client := proxy.client()
+	r := resourceKey()
+
+	config := testKeyConfig()
+	state := applyResource(t, r, nil, config, client)
+
+	d := r.Data(state)
+	for attr, want := range config {
+		if got := d.Get(attr); !reflect.DeepEqual(got, want) {
+			t.Errorf("after create, %s = %#v, want %#v", attr, got, want)
+		}
+	}
+
+	state = refreshResource(t, r, state, client)
+	assertNoDiff(t, r, state, config, client)
+
+	updated := updatedKeyConfig()
+	state = applyResource(t, r, state, updated, client)
+
+	sent := proxy.lastRequest("/key/update")
+	if sent == nil {
+		t.Fatal("expected a /key/update request")
+	}
+	for attr, want := range updated {
+		if attr == "user_id" || attr == "send_invite_email" {
+			continue
+		}
+		if got := sent[attr]; !reflect.DeepEqual(got, normalizeJSON(t, want)) {
+			t.Errorf("/key/update sent %s = %#v, want %#v", attr, got, want)
+		}
+	}
+
+	state = refreshResource(t, r, state, client)
+	assertNoDiff(t, r, state, updated, client)
+
+	d = r.Data(state)
+	for attr, want := range updated {
+		if got := d.Get(attr); !reflect.DeepEqual(got, want) {
+			t.Errorf("after update, %s = %#v, want %#v", attr, got, want)
+		}
+	}
+}
+
+func TestResourceKeyUpdateOmitsUnchangedDuration(t *testing.T) {
+	proxy := newFakeProxy(t)
+	client := proxy.client()
+	r := resourceKey()
+
+	config := testKeyConfig()
+	state := applyResource(t, r, nil, config, client)
+	applyResource(t, r, state, mergeMaps(config, map[string]interface{}{"max_budget": 150.0}), client)
+
+	if _, ok := proxy.lastRequest("/key/update")["duration"]; ok {
+		t.Error("expected duration to be omitted when it did not change, since it restarts the key's expiry")
+	}
+}
+
+func TestResourceKeyForceNewAttributes(t *testing.T) {
+	proxy := newFakeProxy(t)
+	client := proxy.client()
+	r := resourceKey()
+
+	config := testKeyConfig()
+	state := applyResource(t, r, nil, config, client)
+
+	diff := planResource(t, r, state, mergeMaps(config, map[string]interface{}{"user_id": "user-2"}), client)
+	if !diff.RequiresNew() {
+		t.Error("expected changing user_id to replace the key")
+	}
+
+	// send_invite_email only applies when the key is created
+	assertNoDiff(t, r, state, mergeMaps(config, map[string]interface{}{"send_invite_email": false}), client)
+
+	updates := len(proxy.requests["/key/update"])
+	cleared := mergeMaps(config)
+	delete(cleared, "soft_budget")
+	state = applyResource(t, r, state, cleared, client)
+	if state.ID == "" || len(proxy.requests["/key/update"]) != updates+1 {
+		t.Fatal("expected removing soft_budget to update the key in place")
+	}
+	if v, ok := proxy.lastRequest("/key/update")["soft_budget"]; !ok || v != nil {
+		t.Errorf("expected soft_budget to be cleared, got %#v", v)
+	}
+	state = refreshResource(t, r, state, client)
+	assertNoDiff(t, r, state, cleared, client)
+}
+
+func TestResourceKeyClearsUnsetLimits(t *testing.T) {
+	proxy := newFakeProxy(t)
+	client := proxy.client()
+	r := resourceKey()
+
+	config := testKeyConfig()
+	state := applyResource(t, r, nil, config, client)
+
+	updated := mergeMaps(config, map[string]interface{}{"tpm_limit": 0})
+	delete(updated, "max_budget")
+	delete(updated, "models")
+	state = applyResource(t, r, state, updated, client)
+
+	sent := proxy.lastRequest("/key/update")
+	if v, ok := sent["max_budget"]; !ok || v != nil {
+		t.Errorf("expected max_budget to be sent as null, got %#v", v)
+	}
+	if v := sent["tpm_limit"]; v != 0.0 {
+		t.Errorf("expected tpm_limit to be sent as 0, got %#v", v)
+	}
+	if v := sent["models"]; !reflect.DeepEqual(v, []interface{}{}) {
+		t.Errorf("expected models to be sent as an empty list, got %#v", v)
+	}
+
+	state = refreshResource(t, r, state, client)
+	assertNoDiff(t, r, state, updated, client)
+
+	if v := proxy.keys[state.ID]["max_budget"]; v != nil {
+		t.Errorf("expected max_budget to be cleared on the proxy, got %#v", v)
+	}
+	if v := state.Attributes["tpm_limit"]; v != "0" {
+		t.Errorf("expected tpm_limit to be 0 after refresh, got %q", v)
+	}
+}
+
+func TestResourceKeyMetadataJSON(t *testing.T) {
+	proxy := newFakeProxy(t)
+	client := proxy.client()
+	r := resourceKey()
+
+	config := testKeyConfig()
+	delete(config, "metadata")
+	config["metadata_json"] = `{
+  "logging": [{"callback_name": "langfuse", "callback_vars": {"langfuse_host": "https://cloud.langfuse.com"}}],
+  "ict": true,
+  "owner": "platform"
+}`
+	state := applyResource(t, r, nil, config, client)
+
+	sent := proxy.lastRequest("/key/generate")
+	if v := sent["metadata"].(map[string]interface{})["ict"]; v != true {
+		t.Errorf("expected metadata.ict to be sent as a boolean, got %#v", v)
+	}
+
+	state = refreshResource(t, r, state, client)
+	assertNoDiff(t, r, state, config, client)
+
+	reordered := mergeMaps(config, map[string]interface{}{
+		"metadata_json": `{"owner":"platform","ict":true,"logging":[{"callback_vars":{"langfuse_host":"https://cloud.langfuse.com"},"callback_name":"langfuse"}]}`,
+	})
+	assertNoDiff(t, r, state, reordered, client)
+
+	if v, ok := state.Attributes["metadata.%"]; ok && v != "0" {
+		t.Errorf("expected the flat metadata map to stay empty, got %s entries", v)
+	}
+}
+
+func TestResourceKeyModelBudgets(t *testing.T) {
+	proxy := newFakeProxy(t)
+	client := proxy.client()
+	r := resourceKey()
+
+	config := testKeyConfig()
+	delete(config, "model_max_budget")
+	config["model_budget"] = []interface{}{
+		map[string]interface{}{"model": "gpt-4", "budget_limit": 100.0, "time_period": "1mo"},
+		map[string]interface{}{"model": "claude-3", "budget_limit": 25.5, "time_period": "7d"},
+	}
+	state := applyResource(t, r, nil, config, client)
+
+	want := map[string]interface{}{
+		"gpt-4":    map[string]interface{}{"budget_limit": 100.0, "time_period": "1mo"},
+		"claude-3": map[string]interface{}{"budget_limit": 25.5, "time_period": "7d"},
+	}
+	if got := proxy.lastRequest("/key/generate")["model_max_budget"]; !reflect.DeepEqual(got, want) {
+		t.Errorf("/key/generate sent model_max_budget = %#v, want %#v", got, want)
+	}
+
+	state = refreshResource(t, r, state, client)
+	assertNoDiff(t, r, state, config, client)
+
+	if n := r.Data(state).Get("model_budget").(*schema.Set).Len(); n != 2 {
+		t.Errorf("expected 2 model_budget blocks after refresh, got %d", n)
+	}
+}
+
+func TestResourceKeyModelBudgetsRejectDuplicates(t *testing.T) {
+	r := resourceKey()
+
+	config := map[string]interface{}{
+		"model_budget": []interface{}{
+			map[string]interface{}{"model": "gpt-4", "budget_limit": 100.0, "time_period": "1mo"},
+			map[string]interface{}{"model": "gpt-4", "budget_limit": 50.0, "time_period": "1d"},
+		},
+	}
+	if _, err := r.Diff(context.Background(), nil, terraform.NewResourceConfigRaw(config), nil); err == nil {
+		t.Error("expected an error for two model_budget blocks with the same model")
+	}
+}
+
+func TestResourceKeyPGPEncryption(t *testing.T) {
+	proxy := newFakeProxy(t)
+	client := proxy.client()
+	r := resourceKey()
+
+	entity, err := openpgp.NewEntity("Team", "", "team@example.com", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var publicKey bytes.Buffer
+	if err := entity.Serialize(&publicKey); err != nil {
+		t.Fatal(err)
+	}
+
+	config := mergeMaps(testKeyConfig(), map[string]interface{}{
+		"pgp_key":     "keybase:team",
+		"pgp_keyring": map[string]interface{}{"team": base64.StdEncoding.EncodeToString(publicKey.Bytes())},
+	})
+	state := applyResource(t, r, nil, config, client)
+
+	if v := state.Attributes["key"]; v != "" {
+		t.Errorf("expected the plaintext key to stay out of state, got %q", v)
+	}
+	if got, want := state.Attributes["key_fingerprint"], hex.EncodeToString(entity.PrimaryKey.Fingerprint); got != want {
+		t.Errorf("key_fingerprint = %q, want %q", got, want)
+	}
+
+	ciphertext, err := base64.StdEncoding.DecodeString(state.Attributes["encrypted_key"])
+	if err != nil {
+		t.Fatal(err)
+	}
+	md, err := openpgp.ReadMessage(bytes.NewReader(ciphertext), openpgp.EntityList{entity}, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	secret, err := io.ReadAll(md.UnverifiedBody)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if hashToken(string(secret)) != state.ID {
+		t.Errorf("encrypted_key decrypts to %q, which is not the generated key", secret)
+	}
+}
+
+func TestResourceKeyPGPKeyValidatedAtPlan(t *testing.T) {
+	r := resourceKey()
+
+	for _, pgpKey := range []string{"keybase:missing", "bm90IGEga2V5"} {
+		config := map[string]interface{}{"pgp_key": pgpKey}
+		if _, err := r.Diff(context.Background(), nil, terraform.NewResourceConfigRaw(config), nil); err == nil {
+			t.Errorf("expected an error planning with pgp_key %q", pgpKey)
+		}
+	}
+}
+
+func TestResourceKeyExpiry(t *testing.T) {
+	proxy := newFakeProxy(t)
+	client := proxy.client()
+	r := resourceKey()
+
+	config := mergeMaps(testKeyConfig(), map[string]interface{}{
+		"duration":             "1d",
+		"replace_when_expired": true,
+		"renew_before":         "1h",
+	})
+	state := applyResource(t, r, nil, config, client)
+
+	if state.Attributes["expires_at"] == "" {
+		t.Fatal("expected expires_at to be set for a key with a duration")
+	}
+	if state.Attributes["is_expired"] != "false" {
+		t.Errorf("expected is_expired to be false, got %q", state.Attributes["is_expired"])
+	}
+	assertNoDiff(t, r, state, config, client)
+
+	// Move the key into the renewal window
+	proxy.keys[state.ID]["expires"] = time.Now().Add(30 * time.Minute).UTC().Format(time.RFC3339)
+	state = refreshResource(t, r, state, client)
+	if state.Attributes["is_expired"] != "false" {
+		t.Errorf("expected a key in the renewal window not to be expired yet")
+	}
+	if diff := planResource(t, r, state, config, client); !diff.RequiresNew() {
+		t.Error("expected a key within renew_before of its expiry to be replaced")
+	}
+
+	proxy.keys[state.ID]["expires"] = time.Now().Add(-time.Minute).UTC().Format(time.RFC3339)
+	state = refreshResource(t, r, state, client)
+	if state.Attributes["is_expired"] != "true" {
+		t.Errorf("expected is_expired to be true after expiry, got %q", state.Attributes["is_expired"])
+	}
+
+	withoutReplace := mergeMaps(config, map[string]interface{}{"replace_when_expired": false})
+	delete(withoutReplace, "renew_before")
+	if diff := planResource(t, r, state, withoutReplace, client); diff.RequiresNew() {
+		t.Error("expected an expired key not to be replaced without replace_when_expired")
+	}
+}
+
+func TestResourceKeyTempBudgetIncrease(t *testing.T) {
+	proxy := newFakeProxy(t)
+	client := proxy.client()
+	r := resourceKey()
+
+	expiry := time.Now().Add(7 * 24 * time.Hour).UTC().Truncate(time.Second)
+	config := mergeMaps(testKeyConfig(), map[string]interface{}{
+		"temp_budget_increase": 50.0,
+		"temp_budget_expiry":   expiry.Format(time.RFC3339),
+	})
+	state := applyResource(t, r, nil, config, client)
+
+	sent := proxy.lastRequest("/key/update")
+	if sent["temp_budget_increase"] != 50.0 {
+		t.Errorf("expected the temporary budget increase to be set through /key/update, got %#v", sent["temp_budget_increase"])
+	}
+
+	d := r.Data(state)
+	if got := d.Get("effective_max_budget").(float64); got != 150.0 {
+		t.Errorf("effective_max_budget = %v, want 150", got)
+	}
+	if got := d.Get("temp_budget_expires_at").(string); got != expiry.Format(time.RFC3339) {
+		t.Errorf("temp_budget_expires_at = %q, want %q", got, expiry.Format(time.RFC3339))
+	}
+	if _, ok := d.Get("metadata").(map[string]interface{})["temp_budget_increase"]; ok {
+		t.Error("expected the temporary budget increase to be kept out of metadata")
+	}
+
+	// Once the increase has expired the proxy stops applying it and may drop it
+	past := time.Now().Add(-time.Hour).UTC().Format(time.RFC3339)
+	metadata := proxy.keys[state.ID]["metadata"].(map[string]interface{})
+	metadata["temp_budget_expiry"] = past
+	expired := mergeMaps(config, map[string]interface{}{"temp_budget_expiry": past})
+	state = refreshResource(t, r, state, client)
+	assertNoDiff(t, r, state, expired, client)
+
+	if got := r.Data(state).Get("effective_max_budget").(float64); got != 100.0 {
+		t.Errorf("effective_max_budget after expiry = %v, want 100", got)
+	}
+
+	delete(metadata, "temp_budget_increase")
+	delete(metadata, "temp_budget_expiry")
+	state = refreshResource(t, r, state, client)
+	assertNoDiff(t, r, state, expired, client)
+
+	// Removing an increase that still applies clears it on the proxy
+	state = applyResource(t, r, nil, config, client)
+	state = applyResource(t, r, state, testKeyConfig(), client)
+	sent = proxy.lastRequest("/key/update")
+	for _, attr := range []string{"temp_budget_increase", "temp_budget_expiry"} {
+		if v, ok := sent[attr]; !ok || v != nil {
+			t.Errorf("expected %s to be cleared, got %#v", attr, v)
+		}
+	}
+	if got := r.Data(state).Get("effective_max_budget").(float64); got != 100.0 {
+		t.Errorf("effective_max_budget after removing the increase = %v, want 100", got)
+	}
+	state = refreshResource(t, r, state, client)
+	assertNoDiff(t, r, state, testKeyConfig(), client)
+}
+
+func TestResourceKeyAccessPolicy(t *testing.T) {
+	proxy := newFakeProxy(t)
+	client := proxy.client()
+	r := resourceKey()
+
+	readOnly := mergeMaps(testKeyConfig(), map[string]interface{}{
+		"key_type":        "read_only",
+		"enforced_params": []interface{}{"user", "metadata.generation_name"},
+	})
+	state := applyResource(t, r, nil, readOnly, client)
+	state = refreshResource(t, r, state, client)
+	assertNoDiff(t, r, state, readOnly, client)
+
+	d := r.Data(state)
+	if got := d.Get("allowed_routes"); !reflect.DeepEqual(got, []interface{}{"info_routes"}) {
+		t.Errorf("allowed_routes for a read_only key = %#v, want [info_routes]", got)
+	}
+	if _, ok := d.Get("metadata").(map[string]interface{})["enforced_params"]; ok {
+		t.Error("expected enforced_params to be kept out of metadata")
+	}
+
+	if diff := planResource(t, r, state, mergeMaps(readOnly, map[string]interface{}{"key_type": "management"}), client); !diff.RequiresNew() {
+		t.Error("expected changing key_type to replace the key")
+	}
+
+	routes := mergeMaps(testKeyConfig(), map[string]interface{}{
+		"allowed_routes":  []interface{}{"/chat/completions", "/embeddings"},
+		"enforced_params": []interface{}{"user"},
+	})
+	state = applyResource(t, r, nil, routes, client)
+	updated := mergeMaps(routes, map[string]interface{}{
+		"allowed_routes":  []interface{}{"llm_api_routes"},
+		"enforced_params": []interface{}{"user", "metadata.trace_id"},
+	})
+	state = applyResource(t, r, state, updated, client)
+
+	sent := proxy.lastRequest("/key/update")
+	for _, attr := range []string{"allowed_routes", "enforced_params"} {
+		if got, want := sent[attr], normalizeJSON(t, updated[attr]); !reflect.DeepEqual(got, want) {
+			t.Errorf("/key/update sent %s = %#v, want %#v", attr, got, want)
+		}
+	}
+
+	state = refreshResource(t, r, state, client)
+	assertNoDiff(t, r, state, updated, client)
+
+	// Removing allowed_routes lifts the restriction
+	state = applyResource(t, r, state, testKeyConfig(), client)
+	if got, ok := proxy.lastRequest("/key/update")["allowed_routes"]; !ok || !reflect.DeepEqual(got, []interface{}{}) {
+		t.Errorf("expected allowed_routes to be cleared, got %#v", got)
+	}
+	state = refreshResource(t, r, state, client)
+	assertNoDiff(t, r, state, testKeyConfig(), client)
+}
+
+func TestResourceKeyImportWithKeyType(t *testing.T) {
+	proxy := newFakeProxy(t)
+	client := proxy.client()
+	r := resourceKey()
+
+	config := map[string]interface{}{"key_alias": "reporting", "key_type": "read_only"}
+	created := applyResource(t, r, nil, config, client)
+
+	imported, err := r.Importer.StateContext(context.Background(), r.Data(&terraform.InstanceState{ID: "reporting"}), client)
+	if err != nil {
+		t.Fatalf("error importing: %s", err)
+	}
+	state := refreshResource(t, r, imported[0].State(), client)
+	if state.ID != created.ID {
+		t.Fatalf("expected key %s to be imported, got %s", created.ID, state.ID)
+	}
+	assertNoDiff(t, r, state, config, client)
+
+	// A key_type that doesn't match the imported key's routes still replaces it
+	if diff := planResource(t, r, state, mergeMaps(config, map[string]interface{}{"key_type": "management"}), client); !diff.RequiresNew() {
+		t.Error("expected a different key_type to replace the key")
+	}
+}
+
+func TestResourceKeyAccessPolicyValidation(t *testing.T) {
+	r := resourceKey()
+
+	for _, config := range []map[string]interface{}{
+		{"key_type": "admin"},
+		{"allowed_routes": []interface{}{"chat completions"}},
+		{"enforced_params": []interface{}{"metadata..user"}},
+		{"key_type": "read_only", "allowed_routes": []interface{}{"/key/info"}},
+	} {
+		if diags := r.Validate(terraform.NewResourceConfigRaw(config)); !diags.HasError() {
+			t.Errorf("expected %v to be rejected", config)
+		}
+	}
+}
+
+func TestResourceKeyServiceAccount(t *testing.T) {
+	proxy := newFakeProxy(t)
+	client := proxy.client()
+	r := resourceKey()
+
+	config := testKeyConfig()
+	delete(config, "user_id")
+	config["service_account_id"] = "billing-service"
+	state := applyResource(t, r, nil, config, client)
+
+	sent := proxy.lastRequest("/key/service-account/generate")
+	if sent == nil {
+		t.Fatal("expected the key to be created through /key/service-account/generate")
+	}
+	if _, ok := sent["user_id"]; ok {
+		t.Error("expected a service account key not to be owned by a user")
+	}
+
+	state = refreshResource(t, r, state, client)
+	assertNoDiff(t, r, state, config, client)
+	if _, ok := r.Data(state).Get("metadata").(map[string]interface{})["service_account_id"]; ok {
+		t.Error("expected service_account_id to be kept out of metadata")
+	}
+
+	updated := mergeMaps(config, map[string]interface{}{"metadata": map[string]interface{}{"environment": "staging"}})
+	state = applyResource(t, r, state, updated, client)
+	state = refreshResource(t, r, state, client)
+	assertNoDiff(t, r, state, updated, client)
+
+	if got := r.Data(state).Get("service_account_id"); got != "billing-service" {
+		t.Errorf("service_account_id after a metadata update = %q, want billing-service", got)
+	}
+
+	diags := r.Validate(terraform.NewResourceConfigRaw(map[string]interface{}{"service_account_id": "billing-service"}))
+	if !diags.HasError() {
+		t.Error("expected service_account_id without team_id to be rejected")
+	}
+}
+
+func TestResourceKeyImport(t *testing.T) {
+	proxy := newFakeProxy(t)
+	client := proxy.client()
+	r := resourceKey()
+
+	created := applyResource(t, r, nil, testKeyConfig(), client)
+	secret := created.Attributes["key"]
+
+	ids := map[string]string{
+		"plaintext key": secret,
+		"hashed token":  created.ID,
+		"key alias":     "key-1",
+	}
+	for name, id := range ids {
+		imported, err := r.Importer.StateContext(context.Background(), r.Data(&terraform.InstanceState{ID: id}), client)
+		if err != nil {
+			t.Fatalf("%s: error importing: %s", name, err)
+		}
+		state := refreshResource(t, r, imported[0].State(), client)
+		if state == nil || state.ID != created.ID {
+			t.Fatalf("%s: expected the key to be imported as %s, got %v", name, created.ID, state)
+		}
+		if v := state.Attributes["key_alias"]; v != "key-1" {
+			t.Errorf("%s: expected the key's settings to be read, got key_alias %q", name, v)
+		}
+		if v := state.Attributes["key"]; (name == "plaintext key") != (v == secret) {
+			t.Errorf("%s: unexpected key in state %q", name, v)
+		}
+	}
+
+	if _, err := r.Importer.StateContext(context.Background(), r.Data(&terraform.InstanceState{ID: "missing-alias"}), client); err == nil {
+		t.Error("expected an error for an unknown key alias")
+	}
+}
+
+func TestResourceKeyRemovedWhenNotFound(t *testing.T) {
+	proxy := newFakeProxy(t)
+	client := proxy.client()
+	r := resourceKey()
+
+	state := applyResource(t, r, nil, testKeyConfig(), client)
+	delete(proxy.keys, state.ID)
+
+	if state := refreshResource(t, r, state, client); state != nil && state.ID != "" {
+		t.Errorf("expected the deleted key to be removed from state, got %v", state)
+	}
+}
+
+func TestResourceKeyStateUpgradeV0(t *testing.T) {
+	token := hashToken("sk-1234")
+
+	cases := map[string]struct {
+		v0   map[string]interface{}
+		want map[string]interface{}
+	}{
+		"plaintext ID": {
+			v0:   map[string]interface{}{"id": "sk-1234", "key": "sk-1234", "key_alias": "ci"},
+			want: map[string]interface{}{"id": token, "key": "sk-1234", "key_alias": "ci"},
+		},
+		"plaintext ID without key": {
+			v0:   map[string]interface{}{"id": "sk-1234", "key_alias": "ci"},
+			want: map[string]interface{}{"id": token, "key": "sk-1234", "key_alias": "ci"},
+		},
+		"already hashed": {
+			v0:   map[string]interface{}{"id": token, "key": "sk-1234"},
+			want: map[string]interface{}{"id": token, "key": "sk-1234"},
+		},
+	}
+	for name, tc := range cases {
+		got, err := resourceKeyStateUpgradeV0(context.Background(), tc.v0, nil)
+		if err != nil {
+			t.Fatalf("%s: error upgrading state: %s", name, err)
+		}
+		if !reflect.DeepEqual(got, tc.want) {
+			t.Errorf("%s: got %#v, want %#v", name, got, tc.want)
+		}
+	}
+}
+
+func TestResourceKeyRotationTriggers(t *testing.T) {
+	proxy := newFakeProxy(t)
+	client := proxy.client()
+	r := resourceKey()
+
+	config := mergeMaps(testKeyConfig(), map[string]interface{}{
+		"rotation_triggers": map[string]interface{}{"version": "1"},
+	})
+	state := applyResource(t, r, nil, config, client)
+	oldID, oldSecret := state.ID, state.Attributes["key"]
+	proxy.keys[oldID]["spend"] = 12.5
+
+	// Make the previous rotation clearly older than the new one
+	state.Attributes["last_rotated_at"] = "2024-01-01T00:00:00Z"
+
+	state = applyResource(t, r, state, mergeMaps(config, map[string]interface{}{
+		"rotation_triggers": map[string]interface{}{"version": "2"},
+	}), client)
+
+	if state.ID == oldID || state.Attributes["key"] == oldSecret {
+		t.Fatal("expected the key to be rotated")
+	}
+	if state.ID != hashToken(state.Attributes["key"]) {
+		t.Errorf("expected the ID to be the new key's hashed token, got %s", state.ID)
+	}
+	if _, ok := proxy.keys[oldID]; ok {
+		t.Error("expected the old token to be gone from the proxy")
+	}
+	if v := state.Attributes["spend"]; v != "12.5" {
+		t.Errorf("expected the key to keep its spend, got %s", v)
+	}
+	if v := state.Attributes["key_alias"]; v != "key-1" {
+		t.Errorf("expected the key to keep its settings, got key_alias %q", v)
+	}
+	if v := state.Attributes["last_rotated_at"]; v == "" || v == "2024-01-01T00:00:00Z" {
+		t.Errorf("expected last_rotated_at to be updated, got %q", v)
+	}
+	if v := proxy.lastRequest("/key/update")["key"]; v != state.ID {
+		t.Errorf("expected the update to target the new token, got %v", v)
+	}
+}
+
+func TestResourceKeyRotationPeriod(t *testing.T) {
+	proxy := newFakeProxy(t)
+	client := proxy.client()
+	r := resourceKey()
+
+	config := mergeMaps(testKeyConfig(), map[string]interface{}{"rotation_period": "30d"})
+	state := applyResource(t, r, nil, config, client)
+	if state.Attributes["last_rotated_at"] == "" {
+		t.Fatal("expected last_rotated_at to be set on create")
+	}
+	assertNoDiff(t, r, state, config, client)
+
+	// A key that becomes due between plan and apply is left for the next plan
+	oldID := state.ID
+	updated := mergeMaps(config, map[string]interface{}{"max_budget": 150.0})
+	plan := planResource(t, r, state, updated, client)
+	stale := state.DeepCopy()
+	stale.Attributes["last_rotated_at"] = time.Now().AddDate(0, 0, -31).UTC().Format(time.RFC3339)
+	newState, diags := r.Apply(context.Background(), stale, plan, client)
+	if diags.HasError() {
+		t.Fatalf("error applying: %v", diags)
+	}
+	if newState.ID != oldID || len(proxy.requests["/key/"+oldID+"/regenerate"]) != 0 {
+		t.Error("expected a rotation that wasn't planned not to happen")
+	}
+	state = newState
+
+	diff := planResource(t, r, state, updated, client)
+	if diff == nil || diff.Attributes["last_rotated_at"] == nil || !diff.Attributes["last_rotated_at"].NewComputed {
+		t.Fatalf("expected a rotation to be planned once rotation_period passed, got %v", diff)
+	}
+
+	state = applyResource(t, r, state, updated, client)
+	if state.ID == oldID {
+		t.Error("expected the key to be rotated")
+	}
+	assertNoDiff(t, r, state, updated, client)
+}
+
+func TestKeyRotationDue(t *testing.T) {
+	now := time.Date(2025, 6, 30, 12, 0, 0, 0, time.UTC)
+
+	cases := []struct {
+		lastRotatedAt string
+		period        string
+		want          bool
+	}{
+		{"", "30d", false},
+		{"2025-06-01T12:00:00Z", "", false},
+		{"2025-06-01T12:00:00Z", "30d", false},
+		{"2025-05-31T12:00:00Z", "30d", true},
+		{"2025-05-01T12:00:00Z", "30d", true},
+		{"2025-06-30T11:00:00Z", "1h", true},
+		{"2025-06-30T11:30:00Z", "1h", false},
+	}
+	for _, tc := range cases {
+		got, err := keyRotationDue(tc.lastRotatedAt, tc.period, now)
+		if err != nil {
+			t.Fatalf("keyRotationDue(%q, %q): %s", tc.lastRotatedAt, tc.period, err)
+		}
+		if got != tc.want {
+			t.Errorf("keyRotationDue(%q, %q) = %t, want %t", tc.lastRotatedAt, tc.period, got, tc.want)
+		}
+	}
+
+	if _, err := keyRotationDue("yesterday", "30d", now); err == nil {
+		t.Error("expected an error for an invalid timestamp")
+	}
+}
+
+func TestResourceKeyAutoRotate(t *testing.T) {
+	proxy := newFakeProxy(t)
+	client := proxy.client()
+	r := resourceKey()
+
+	config := mergeMaps(testKeyConfig(), map[string]interface{}{
+		"auto_rotate":       true,
+		"rotation_interval": "30d",
+	})
+	state := applyResource(t, r, nil, config, client)
+	sent := proxy.lastRequest("/key/generate")
+	if sent["auto_rotate"] != true || sent["rotation_interval"] != "30d" {
+		t.Errorf("expected rotation settings on create, got auto_rotate %v and rotation_interval %v", sent["auto_rotate"], sent["rotation_interval"])
+	}
+	assertNoDiff(t, r, state, config, client)
+
+	state = applyResource(t, r, state, mergeMaps(config, map[string]interface{}{"max_budget": 150.0}), client)
+	if _, ok := proxy.lastRequest("/key/update")["auto_rotate"]; ok {
+		t.Error("expected unchanged rotation settings not to be sent")
+	}
+
+	disabled := mergeMaps(testKeyConfig(), map[string]interface{}{"max_budget": 150.0})
+	state = applyResource(t, r, state, disabled, client)
+	sent = proxy.lastRequest("/key/update")
+	if v, ok := sent["auto_rotate"]; !ok || v != false {
+		t.Errorf("expected auto_rotate to be turned off, got %#v", v)
+	}
+	if v, ok := sent["rotation_interval"]; !ok || v != nil {
+		t.Errorf("expected rotation_interval to be cleared, got %#v", v)
+	}
+	state = refreshResource(t, r, state, client)
+	assertNoDiff(t, r, state, disabled, client)
+}
+
+func TestResourceKeyAutoRotateRefresh(t *testing.T) {
+	proxy := newFakeProxy(t)
+	client := proxy.client()
+	r := resourceKey()
+
+	config := mergeMaps(testKeyConfig(), map[string]interface{}{
+		"auto_rotate":       true,
+		"rotation_interval": "30d",
+	})
+	state := applyResource(t, r, nil, config, client)
+
+	// Rotated by the proxy, the key is found again by its alias
+	record := proxy.keys[state.ID]
+	delete(proxy.keys, state.ID)
+	record["token"] = hashToken(proxy.newSecret())
+	proxy.keys[record["token"].(string)] = record
+	state = refreshResource(t, r, state, client)
+	if state == nil || state.ID != record["token"] {
+		t.Fatalf("expected refresh to follow the rotated key, got %v", state)
+	}
+
+	// Deleted, it is removed from state
+	delete(proxy.keys, state.ID)
+	if state := refreshResource(t, r, state, client); state != nil && state.ID != "" {
+		t.Errorf("expected the deleted key to be removed from state, got %v", state)
+	}
+}
+
+func TestResourceKeySecretsNotLogged(t *testing.T) {
+	proxy := newFakeProxy(t)
+	client := proxy.client()
+	r := resourceKey()
+	logs := captureLogs(t)
+
+	config := mergeMaps(testKeyConfig(), map[string]interface{}{
+		"rotation_triggers": map[string]interface{}{"version": "1"},
+	})
+	state := applyResource(t, r, nil, config, client)
+	created := state.Attributes["key"]
+	state = applyResource(t, r, state, mergeMaps(config, map[string]interface{}{
+		"rotation_triggers": map[string]interface{}{"version": "2"},
+	}), client)
+
+	for _, secret := range []string{created, state.Attributes["key"]} {
+		if secret == "" || strings.Contains(logs.String(), secret) {
+			t.Errorf("expected key %q not to be logged", secret)
+		}
+	}
+	if !strings.Contains(logs.String(), `"key":"REDACTED"`) {
+		t.Error("expected the logged responses to show the key as redacted")
+	}
+}
@@ -1,10 +1,13 @@
 package litellm
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
 	"log"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 )
 
 func buildKeyData(d *schema.ResourceData) map[string]interface{} {
@@ -130,6 +133,110 @@ func expandStringList(list []interface{}) []string {
 	return result
 }
 
+// expandInterfaceStringList converts a JSON list of strings to []string. It
+// returns nil if v is not a list.
+func expandInterfaceStringList(v interface{}) []string {
+	list, ok := v.([]interface{})
+	if !ok {
+		return nil
+	}
+
+	result := make([]string, 0, len(list))
+	for _, item := range list {
+		if s, ok := item.(string); ok {
+			result = append(result, s)
+		}
+	}
+	return result
+}
+
+// modelBudgetSchema returns the schema of the repeatable model_budget block
+// shared by keys and teams.
+func modelBudgetSchema(conflictsWith ...string) *schema.Schema {
+	return &schema.Schema{
+		Type:          schema.TypeSet,
+		Optional:      true,
+		ConflictsWith: conflictsWith,
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"model": {
+					Type:     schema.TypeString,
+					Required: true,
+				},
+				"budget_limit": {
+					Type:     schema.TypeFloat,
+					Required: true,
+				},
+				"time_period": {
+					Type:         schema.TypeString,
+					Required:     true,
+					ValidateFunc: validation.StringMatch(liteLLMDurationPattern, "must be a LiteLLM duration such as \"1d\" or \"1mo\""),
+				},
+			},
+		},
+	}
+}
+
+// expandModelBudgets converts model_budget blocks to the nested
+// {model: {budget_limit, time_period}} format LiteLLM expects in
+// model_max_budget.
+func expandModelBudgets(budgets []interface{}) map[string]interface{} {
+	result := make(map[string]interface{}, len(budgets))
+	for _, b := range budgets {
+		budget := b.(map[string]interface{})
+		result[budget["model"].(string)] = map[string]interface{}{
+			"budget_limit": budget["budget_limit"].(float64),
+			"time_period":  budget["time_period"].(string),
+		}
+	}
+	return result
+}
+
+// flattenModelMaxBudget splits model_max_budget as returned by LiteLLM into
+// flat per-model budgets, used by older proxies, and model_budget blocks.
+func flattenModelMaxBudget(modelMaxBudget map[string]interface{}) (map[string]interface{}, []interface{}) {
+	maxBudgets := make(map[string]interface{})
+	var budgets []interface{}
+	for model, v := range modelMaxBudget {
+		switch budget := v.(type) {
+		case float64:
+			maxBudgets[model] = budget
+		case map[string]interface{}:
+			limit, _ := budget["budget_limit"].(float64)
+			period, _ := budget["time_period"].(string)
+			budgets = append(budgets, map[string]interface{}{
+				"model":        model,
+				"budget_limit": limit,
+				"time_period":  period,
+			})
+		}
+	}
+	return maxBudgets, budgets
+}
+
+// validateModelBudgets rejects more than one model_budget block for a model,
+// since LiteLLM keys per-model budgets by model name.
+func validateModelBudgets(budgets []interface{}) error {
+	seen := make(map[string]bool, len(budgets))
+	for _, b := range budgets {
+		model, _ := b.(map[string]interface{})["model"].(string)
+		if model == "" {
+			continue
+		}
+		if seen[model] {
+			return fmt.Errorf("model_budget: model %q is configured more than once", model)
+		}
+		seen[model] = true
+	}
+	return nil
+}
+
+// hashToken returns the hashed token LiteLLM stores for a plaintext key.
+func hashToken(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
 func mapToKey(data map[string]interface{}) *Key {
 	key := &Key{}
 	for k, v := range data {
@@ -139,25 +246,25 @@ func mapToKey(data map[string]interface{}) *Key {
 		case "models":
 			key.Models = v.([]string)
 		case "max_budget":
-			key.MaxBudget = v.(float64)
+			key.MaxBudget = v.(*float64)
 		case "user_id":
 			key.UserID = v.(string)
 		case "team_id":
 			key.TeamID = v.(string)
 		case "max_parallel_requests":
-			key.MaxParallelRequests = v.(int)
+			key.MaxParallelRequests = v.(*int)
 		case "metadata":
 			key.Metadata = v.(map[string]interface{})
 		case "tpm_limit":
-			key.TPMLimit = v.(int)
+			key.TPMLimit = v.(*int)
 		case "rpm_limit":
-			key.RPMLimit = v.(int)
+			key.RPMLimit = v.(*int)
 		case "budget_duration":
 			key.BudgetDuration = v.(string)
 		case "allowed_cache_controls":
 			key.AllowedCacheControls = v.([]string)
 		case "soft_budget":
-			key.SoftBudget = v.(float64)
+			key.SoftBudget = v.(*float64)
 		case "key_alias":
 			key.KeyAlias = v.(string)
 		case "duration":
@@ -1,7 +1,9 @@
 package litellm
 
 import (
+	"github.com/hashicorp/go-cty/cty"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/structure"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 )
 
@@ -12,6 +14,12 @@ func resourceLiteLLMModel() *schema.Resource {
 		Update: resourceLiteLLMModelUpdate,
 		Delete: resourceLiteLLMModelDelete,
 
+		ValidateRawResourceConfigFuncs: []schema.ValidateRawResourceConfigFunc{
+			validation.PreferWriteOnlyAttribute(cty.GetAttrPath("model_api_key"), cty.GetAttrPath("model_api_key_wo")),
+			validation.PreferWriteOnlyAttribute(cty.GetAttrPath("aws_secret_access_key"), cty.GetAttrPath("aws_secret_access_key_wo")),
+			validation.PreferWriteOnlyAttribute(cty.GetAttrPath("vertex_credentials"), cty.GetAttrPath("vertex_credentials_wo")),
+		},
+
 		Schema: map[string]*schema.Schema{
 			"model_name": {
 				Type:     schema.TypeString,
@@ -57,9 +65,22 @@ func resourceLiteLLMModel() *schema.Resource {
 				Optional: true,
 			},
 			"model_api_key": {
-				Type:      schema.TypeString,
-				Optional:  true,
-				Sensitive: true,
+				Type:          schema.TypeString,
+				Optional:      true,
+				Sensitive:     true,
+				ConflictsWith: []string{"model_api_key_wo"},
+			},
+			"model_api_key_wo": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				Sensitive:     true,
+				WriteOnly:     true,
+				ConflictsWith: []string{"model_api_key"},
+			},
+			"model_api_key_wo_version": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				RequiredWith: []string{"model_api_key_wo"},
 			},
 			"model_api_base": {
 				Type:     schema.TypeString,
@@ -90,6 +111,12 @@ func resourceLiteLLMModel() *schema.Resource {
 					"audio_transcription",
 				}, false),
 			},
+			"metadata_json": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				ValidateFunc:     validateJSONObject,
+				DiffSuppressFunc: structure.SuppressJsonDiff,
+			},
 			"input_cost_per_million_tokens": {
 				Type:     schema.TypeFloat,
 				Optional: true,
@@ -120,9 +147,22 @@ func resourceLiteLLMModel() *schema.Resource {
 				Sensitive: true,
 			},
 			"aws_secret_access_key": {
-				Type:      schema.TypeString,
-				Optional:  true,
-				Sensitive: true,
+				Type:          schema.TypeString,
+				Optional:      true,
+				Sensitive:     true,
+				ConflictsWith: []string{"aws_secret_access_key_wo"},
+			},
+			"aws_secret_access_key_wo": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				Sensitive:     true,
+				WriteOnly:     true,
+				ConflictsWith: []string{"aws_secret_access_key"},
+			},
+			"aws_secret_access_key_wo_version": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				RequiredWith: []string{"aws_secret_access_key_wo"},
 			},
 			"aws_region_name": {
 				Type:     schema.TypeString,
@@ -139,8 +179,22 @@ func resourceLiteLLMModel() *schema.Resource {
 				Sensitive: true,
 			},
 			"vertex_credentials": {
-				Type:     schema.TypeString,
-				Optional: true,
+				Type:          schema.TypeString,
+				Optional:      true,
+				Sensitive:     true,
+				ConflictsWith: []string{"vertex_credentials_wo"},
+			},
+			"vertex_credentials_wo": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				Sensitive:     true,
+				WriteOnly:     true,
+				ConflictsWith: []string{"vertex_credentials"},
+			},
+			"vertex_credentials_wo_version": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				RequiredWith: []string{"vertex_credentials_wo"},
 			},
 		},
 	}
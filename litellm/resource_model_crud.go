@@ -7,6 +7,7 @@ import (
 
 	"github.com/google/uuid"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/structure"
 )
 
 // retryModelRead attempts to read a model with exponential backoff
@@ -89,7 +90,7 @@ func createOrUpdateModel(d *schema.ResourceData, m interface{}, isUpdate bool) e
 			CustomLLMProvider:              customLLMProvider,
 			TPM:                            d.Get("tpm").(int),
 			RPM:                            d.Get("rpm").(int),
-			APIKey:                         d.Get("model_api_key").(string),
+			APIKey:                         getWriteOnlyString(d, "model_api_key"),
 			APIBase:                        d.Get("model_api_base").(string),
 			APIVersion:                     d.Get("api_version").(string),
 			Model:                          modelName,
@@ -100,11 +101,11 @@ func createOrUpdateModel(d *schema.ResourceData, m interface{}, isUpdate bool) e
 			InputCostPerSecond:             d.Get("input_cost_per_second").(float64),
 			OutputCostPerSecond:            d.Get("output_cost_per_second").(float64),
 			AWSAccessKeyID:                 d.Get("aws_access_key_id").(string),
-			AWSSecretAccessKey:             d.Get("aws_secret_access_key").(string),
+			AWSSecretAccessKey:             getWriteOnlyString(d, "aws_secret_access_key"),
 			AWSRegionName:                  d.Get("aws_region_name").(string),
 			VertexProject:                  d.Get("vertex_project").(string),
 			VertexLocation:                 d.Get("vertex_location").(string),
-			VertexCredentials:              d.Get("vertex_credentials").(string),
+			VertexCredentials:              getWriteOnlyString(d, "vertex_credentials"),
 			ReasoningEffort:                d.Get("reasoning_effort").(string),
 			Thinking:                       thinking,
 			MergeReasoningContentInChoices: d.Get("merge_reasoning_content_in_choices").(bool),
@@ -119,6 +120,14 @@ func createOrUpdateModel(d *schema.ResourceData, m interface{}, isUpdate bool) e
 		Additional: make(map[string]interface{}),
 	}
 
+	// metadata_json is validated as a JSON object at plan time. The proxy
+	// keeps metadata that isn't sent, so removing it sends an empty object.
+	if v := d.Get("metadata_json").(string); v != "" {
+		modelReq.ModelInfo.Metadata, _ = structure.ExpandJsonFromString(v)
+	} else if isUpdate && d.HasChange("metadata_json") {
+		modelReq.ModelInfo.Metadata = map[string]interface{}{}
+	}
+
 	endpoint := endpointModelNew
 	if isUpdate {
 		endpoint = endpointModelUpdate
@@ -181,6 +190,16 @@ func resourceLiteLLMModelRead(d *schema.ResourceData, m interface{}) error {
 	d.Set("tier", GetStringValue(modelResp.ModelInfo.Tier, d.Get("tier").(string)))
 	d.Set("mode", GetStringValue(modelResp.ModelInfo.Mode, d.Get("mode").(string)))
 
+	// Empty metadata is only reported when metadata_json is set, so that an
+	// unconfigured or removed metadata_json doesn't show a diff
+	if len(modelResp.ModelInfo.Metadata) > 0 || (modelResp.ModelInfo.Metadata != nil && d.Get("metadata_json").(string) != "") {
+		metadataJSON, err := structure.FlattenJsonToString(modelResp.ModelInfo.Metadata)
+		if err != nil {
+			return fmt.Errorf("failed to encode model metadata: %w", err)
+		}
+		d.Set("metadata_json", metadataJSON)
+	}
+
 	// Store sensitive information
 	d.Set("model_api_key", d.Get("model_api_key"))
 	d.Set("aws_access_key_id", d.Get("aws_access_key_id"))
@@ -0,0 +1,119 @@
+package litellm
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/go-cty/cty"
+	ctyjson "github.com/hashicorp/go-cty/cty/json"
+)
+
+func testModelConfig() map[string]interface{} {
+	return map[string]interface{}{
+		"model_name":          "gpt-4o",
+		"custom_llm_provider": "openai",
+		"base_model":          "gpt-4o",
+	}
+}
+
+// writeOnlySecrets are the write-only secret attributes and the request
+// fields the proxy receives them in.
+var writeOnlySecrets = map[string]string{
+	"model_api_key_wo":         "api_key",
+	"aws_secret_access_key_wo": "aws_secret_access_key",
+	"vertex_credentials_wo":    "vertex_credentials",
+}
+
+func writeOnlyModelConfig(secret string, version int) map[string]interface{} {
+	config := testModelConfig()
+	for attr := range writeOnlySecrets {
+		config[attr] = secret + "-" + attr
+		config[attr+"_version"] = version
+	}
+	return config
+}
+
+// assertModelSecrets checks that the last request to path carried the
+// write-only secrets of config.
+func assertModelSecrets(t *testing.T, proxy *fakeProxy, path string, secret string) {
+	t.Helper()
+
+	params, _ := proxy.lastRequest(path)["litellm_params"].(map[string]interface{})
+	for attr, field := range writeOnlySecrets {
+		if want := secret + "-" + attr; params[field] != want {
+			t.Errorf("expected %s to send %s %q, got %v", path, field, want, params[field])
+		}
+	}
+}
+
+// assertNoSecretsInState checks that the write-only attributes are null in
+// state and that no secret value appears anywhere in it.
+func assertNoSecretsInState(t *testing.T, state cty.Value, secret string) {
+	t.Helper()
+
+	for attr := range writeOnlySecrets {
+		if !state.GetAttr(attr).IsNull() {
+			t.Errorf("expected %s not to be stored in state", attr)
+		}
+	}
+	b, err := ctyjson.Marshal(state, state.Type())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(b), secret) {
+		t.Errorf("expected no secret in state, got %s", b)
+	}
+}
+
+func TestResourceModelWriteOnlySecrets(t *testing.T) {
+	proxy := newFakeProxy(t)
+	client := proxy.client()
+
+	state := applyThroughProtocol(t, "litellm_model", cty.NilVal, writeOnlyModelConfig("secret-alpha", 1), client)
+	assertModelSecrets(t, proxy, "/model/new", "secret-alpha")
+	assertNoSecretsInState(t, state, "secret-alpha")
+
+	// A new value alone isn't detected, since it isn't stored
+	state = applyThroughProtocol(t, "litellm_model", state, writeOnlyModelConfig("secret-bravo", 1), client)
+	if n := len(proxy.requests["/model/update"]); n != 0 {
+		t.Fatalf("expected no update without a version change, got %d", n)
+	}
+
+	state = applyThroughProtocol(t, "litellm_model", state, writeOnlyModelConfig("secret-bravo", 2), client)
+	assertModelSecrets(t, proxy, "/model/update", "secret-bravo")
+	assertNoSecretsInState(t, state, "secret-bravo")
+	if v := state.GetAttr("model_api_key_wo_version"); !v.RawEquals(cty.NumberIntVal(2)) {
+		t.Errorf("expected the new version to be stored, got %#v", v)
+	}
+}
+
+func TestResourceModelVertexCredentialsSensitive(t *testing.T) {
+	if !resourceLiteLLMModel().Schema["vertex_credentials"].Sensitive {
+		t.Error("expected vertex_credentials to be sensitive")
+	}
+}
+
+func TestResourceModelMetadataJSONRemoved(t *testing.T) {
+	proxy := newFakeProxy(t)
+	client := proxy.client()
+	r := resourceLiteLLMModel()
+
+	config := mergeMaps(testModelConfig(), map[string]interface{}{
+		"metadata_json": `{"owner": "platform", "limits": {"daily": 10}}`,
+	})
+	state := applyResource(t, r, nil, config, client)
+	state = refreshResource(t, r, state, client)
+	assertNoDiff(t, r, state, config, client)
+
+	unset := testModelConfig()
+	state = applyResource(t, r, state, unset, client)
+	info, _ := proxy.lastRequest("/model/update")["model_info"].(map[string]interface{})
+	if metadata, ok := info["metadata"].(map[string]interface{}); !ok || len(metadata) != 0 {
+		t.Errorf("expected removing metadata_json to send empty metadata, got %#v", info["metadata"])
+	}
+	state = refreshResource(t, r, state, client)
+	if v := state.Attributes["metadata_json"]; v != "" {
+		t.Errorf("expected metadata_json to stay empty, got %q", v)
+	}
+	assertNoDiff(t, r, state, unset, client)
+}
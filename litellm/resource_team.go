@@ -1,14 +1,19 @@
 package litellm
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"io/ioutil"
 	"log"
 	"net/http"
+	"net/url"
+	"strings"
 
 	"github.com/google/uuid"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/structure"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 )
 
 const (
@@ -16,6 +21,33 @@ const (
 	endpointTeamInfo   = "/team/info"
 	endpointTeamUpdate = "/team/update"
 	endpointTeamDelete = "/team/delete"
+
+	endpointTeamBlock   = "/team/block"
+	endpointTeamUnblock = "/team/unblock"
+
+	endpointTeamPermissionsUpdate = "/team/permissions_update"
+)
+
+// teamMemberPermissionRoutes are the key management routes LiteLLM lets team
+// admins grant to the other members of a team.
+var teamMemberPermissionRoutes = []string{
+	"/key/generate",
+	"/key/service-account/generate",
+	"/key/update",
+	"/key/delete",
+	"/key/info",
+	"/key/list",
+	"/key/health",
+	"/key/regenerate",
+	"/key/{key_id}/regenerate",
+	"/key/block",
+	"/key/unblock",
+}
+
+const (
+	teamOnDestroyFail         = "fail"
+	teamOnDestroyDeleteKeys   = "delete_keys"
+	teamOnDestroyReassignKeys = "reassign_keys_to_team"
 )
 
 func ResourceLiteLLMTeam() *schema.Resource {
@@ -25,19 +57,44 @@ func ResourceLiteLLMTeam() *schema.Resource {
 		Update: resourceLiteLLMTeamUpdate,
 		Delete: resourceLiteLLMTeamDelete,
 
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+
 		Schema: map[string]*schema.Schema{
+			"team_id": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.StringIsNotWhiteSpace,
+			},
 			"team_alias": {
 				Type:     schema.TypeString,
 				Required: true,
 			},
+			// Computed, so that a team moved in the UI or left to the proxy
+			// doesn't plan a replacement; see the CustomizeDiff for changes.
+			// The description carries the reason the plan shows
+			// "forces replacement", since CustomizeDiff can't add warnings.
 			"organization_id": {
-				Type:     schema.TypeString,
-				Optional: true,
+				Type:        schema.TypeString,
+				Optional:    true,
+				Computed:    true,
+				Description: "The organization the team belongs to. LiteLLM can't move a team to another organization, so changing this replaces the team; the new team starts without spend, members or keys.",
 			},
 			"metadata": {
-				Type:     schema.TypeMap,
-				Optional: true,
-				Elem:     &schema.Schema{Type: schema.TypeString},
+				Type:          schema.TypeMap,
+				Optional:      true,
+				Elem:          &schema.Schema{Type: schema.TypeString},
+				ConflictsWith: []string{"metadata_json"},
+			},
+			"metadata_json": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				ValidateFunc:     validateJSONObject,
+				DiffSuppressFunc: structure.SuppressJsonDiff,
+				ConflictsWith:    []string{"metadata"},
 			},
 			"tpm_limit": {
 				Type:     schema.TypeInt,
@@ -55,26 +112,128 @@ func ResourceLiteLLMTeam() *schema.Resource {
 				Type:     schema.TypeString,
 				Optional: true,
 			},
+			// models is also computed, so that it can be managed by
+			// litellm_team_models or litellm_team_model instead
 			"models": {
 				Type:     schema.TypeList,
 				Optional: true,
+				Computed: true,
 				Elem:     &schema.Schema{Type: schema.TypeString},
 			},
 			"blocked": {
 				Type:     schema.TypeBool,
 				Optional: true,
 			},
+			"blocked_reason": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			// on_destroy and reassign_keys_to_team_id only affect what
+			// happens to the team's keys when the team is destroyed. Unset,
+			// the team is deleted without looking at its keys, as before.
+			"on_destroy": {
+				Type:     schema.TypeString,
+				Optional: true,
+				ValidateFunc: validation.StringInSlice([]string{
+					teamOnDestroyFail,
+					teamOnDestroyDeleteKeys,
+					teamOnDestroyReassignKeys,
+				}, false),
+			},
+			"reassign_keys_to_team_id": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"model_aliases": {
+				Type:     schema.TypeMap,
+				Optional: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+			"model_rpm_limit": {
+				Type:     schema.TypeMap,
+				Optional: true,
+				Elem:     &schema.Schema{Type: schema.TypeInt},
+			},
+			"model_tpm_limit": {
+				Type:     schema.TypeMap,
+				Optional: true,
+				Elem:     &schema.Schema{Type: schema.TypeInt},
+			},
+			"model_budget": modelBudgetSchema(),
+			// The proxy ignores null defaults for new team members, so they
+			// can't be removed once set and are computed instead
+			"team_member_budget": {
+				Type:     schema.TypeFloat,
+				Optional: true,
+				Computed: true,
+			},
+			"team_member_rpm_limit": {
+				Type:     schema.TypeInt,
+				Optional: true,
+				Computed: true,
+			},
+			"team_member_tpm_limit": {
+				Type:     schema.TypeInt,
+				Optional: true,
+				Computed: true,
+			},
+			"team_member_key_duration": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				ValidateFunc: validation.StringMatch(liteLLMDurationPattern, "must be a LiteLLM duration such as \"30d\" or \"1mo\""),
+			},
+			// Computed, since teams start with the proxy's default
+			// permissions and removing them doesn't reset those
+			"team_member_permissions": {
+				Type:     schema.TypeSet,
+				Optional: true,
+				Computed: true,
+				Elem: &schema.Schema{
+					Type:         schema.TypeString,
+					ValidateFunc: validation.StringInSlice(teamMemberPermissionRoutes, false),
+				},
+			},
 		},
+
+		CustomizeDiff: resourceLiteLLMTeamCustomizeDiff,
+	}
+}
+
+func resourceLiteLLMTeamCustomizeDiff(ctx context.Context, d *schema.ResourceDiff, m interface{}) error {
+	onDestroy := d.Get("on_destroy").(string)
+	targetTeamID := d.Get("reassign_keys_to_team_id").(string)
+	if onDestroy == teamOnDestroyReassignKeys && targetTeamID == "" && d.NewValueKnown("reassign_keys_to_team_id") {
+		return fmt.Errorf("reassign_keys_to_team_id is required when on_destroy is %q", teamOnDestroyReassignKeys)
+	}
+	if onDestroy != teamOnDestroyReassignKeys && targetTeamID != "" {
+		return fmt.Errorf("reassign_keys_to_team_id can only be set when on_destroy is %q", teamOnDestroyReassignKeys)
+	}
+
+	// Most proxy versions reject moving a team to another organization
+	// through /team/update, so a configured change replaces the team
+	if d.Id() != "" && d.HasChange("organization_id") {
+		oldOrg, newOrg := d.GetChange("organization_id")
+		log.Printf("[WARN] Team %s can't be moved from organization %q to %q, so it will be replaced; the new team starts without spend, members or keys",
+			d.Id(), oldOrg, newOrg)
+		if err := d.ForceNew("organization_id"); err != nil {
+			return err
+		}
 	}
+
+	return validateModelBudgets(d.Get("model_budget").(*schema.Set).List())
 }
 
 func resourceLiteLLMTeamCreate(d *schema.ResourceData, m interface{}) error {
 	client := m.(*Client)
 
-	teamID := uuid.New().String()
+	teamID := d.Get("team_id").(string)
+	if teamID == "" {
+		teamID = uuid.New().String()
+	}
 	teamData := buildTeamData(d, teamID)
 
-	log.Printf("[DEBUG] Create team request payload: %+v", teamData)
+	log.Printf("[DEBUG] Create team request payload: %s", redactForLog(teamData))
 
 	resp, err := MakeRequest(client, "POST", endpointTeamNew, teamData)
 	if err != nil {
@@ -89,6 +248,18 @@ func resourceLiteLLMTeamCreate(d *schema.ResourceData, m interface{}) error {
 	d.SetId(teamID)
 	log.Printf("[INFO] Team created with ID: %s", teamID)
 
+	if d.Get("blocked").(bool) {
+		if err := setTeamBlocked(client, teamID, true); err != nil {
+			return err
+		}
+	}
+
+	if v, ok := d.GetOk("team_member_permissions"); ok {
+		if err := setTeamMemberPermissions(client, teamID, expandStringList(v.(*schema.Set).List())); err != nil {
+			return err
+		}
+	}
+
 	return resourceLiteLLMTeamRead(d, m)
 }
 
@@ -97,57 +268,186 @@ func resourceLiteLLMTeamRead(d *schema.ResourceData, m interface{}) error {
 
 	log.Printf("[INFO] Reading team with ID: %s", d.Id())
 
-	resp, err := MakeRequest(client, "GET", fmt.Sprintf("%s?team_id=%s", endpointTeamInfo, d.Id()), nil)
+	team, err := getTeamInfo(client, d.Id())
 	if err != nil {
-		return fmt.Errorf("error reading team: %w", err)
+		return err
 	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode == http.StatusNotFound {
+	if team == nil {
 		log.Printf("[WARN] Team with ID %s not found, removing from state", d.Id())
 		d.SetId("")
 		return nil
 	}
 
-	var teamResp TeamResponse
-	if err := json.NewDecoder(resp.Body).Decode(&teamResp); err != nil {
-		return fmt.Errorf("error decoding team info response: %w", err)
+	d.Set("team_id", d.Id())
+	d.Set("team_alias", team.TeamAlias)
+	d.Set("organization_id", team.OrganizationID)
+	if err := setMetadata(d, team.Metadata); err != nil {
+		return err
+	}
+	d.Set("tpm_limit", team.TPMLimit)
+	d.Set("rpm_limit", team.RPMLimit)
+	d.Set("max_budget", team.MaxBudget)
+	d.Set("budget_duration", team.BudgetDuration)
+	d.Set("models", team.Models)
+	d.Set("blocked", team.Blocked)
+	d.Set("blocked_reason", team.BlockedReason)
+	d.Set("model_aliases", team.ModelAliases)
+	d.Set("model_rpm_limit", team.ModelRPMLimit)
+	d.Set("model_tpm_limit", team.ModelTPMLimit)
+	_, budgets := flattenModelMaxBudget(team.ModelMaxBudget)
+	d.Set("model_budget", budgets)
+	d.Set("team_member_key_duration", team.TeamMemberKeyDuration)
+	if budget := team.TeamMemberBudgetTable; budget != nil {
+		d.Set("team_member_budget", budget.MaxBudget)
+		d.Set("team_member_rpm_limit", budget.RPMLimit)
+		d.Set("team_member_tpm_limit", budget.TPMLimit)
+	} else {
+		d.Set("team_member_budget", nil)
+		d.Set("team_member_rpm_limit", nil)
+		d.Set("team_member_tpm_limit", nil)
 	}
+	d.Set("team_member_permissions", team.TeamMemberPermissions)
 
-	// Update the state with values from the response or fall back to the data passed in during creation
-	d.Set("team_alias", GetStringValue(teamResp.TeamAlias, d.Get("team_alias").(string)))
-	d.Set("organization_id", GetStringValue(teamResp.OrganizationID, d.Get("organization_id").(string)))
+	log.Printf("[INFO] Successfully read team with ID: %s", d.Id())
+	return nil
+}
 
-	// Handle metadata separately as it's a map
-	if teamResp.Metadata != nil {
-		d.Set("metadata", teamResp.Metadata)
-	} else {
-		d.Set("metadata", d.Get("metadata"))
+// getTeamInfo returns the team with the given ID, or nil if it doesn't exist.
+func getTeamInfo(client *Client, teamID string) (*TeamResponse, error) {
+	resp, err := MakeRequest(client, "GET", fmt.Sprintf("%s?team_id=%s", endpointTeamInfo, url.QueryEscape(teamID)), nil)
+	if err != nil {
+		return nil, fmt.Errorf("error reading team: %w", err)
 	}
+	defer resp.Body.Close()
 
-	d.Set("tpm_limit", GetIntValue(teamResp.TPMLimit, d.Get("tpm_limit").(int)))
-	d.Set("rpm_limit", GetIntValue(teamResp.RPMLimit, d.Get("rpm_limit").(int)))
-	d.Set("max_budget", GetFloatValue(teamResp.MaxBudget, d.Get("max_budget").(float64)))
-	d.Set("budget_duration", GetStringValue(teamResp.BudgetDuration, d.Get("budget_duration").(string)))
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("error reading team info response: %w", err)
+	}
 
-	// Handle models separately as it's a list
-	if teamResp.Models != nil {
-		d.Set("models", teamResp.Models)
-	} else {
-		d.Set("models", d.Get("models"))
+	// Depending on the version, the proxy reports a missing team as a 404 or
+	// as an error mentioning that the team was not found
+	if resp.StatusCode == http.StatusNotFound || (resp.StatusCode != http.StatusOK && isTeamNotFoundMessage(string(body))) {
+		return nil, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("error reading team: %s - %s", resp.Status, string(body))
+	}
+
+	var teamResp TeamInfoResponse
+	if err := json.Unmarshal(body, &teamResp); err != nil {
+		return nil, fmt.Errorf("error decoding team info response: %w", err)
+	}
+	if teamResp.TeamInfo.TeamID == "" {
+		return nil, nil
 	}
 
-	d.Set("blocked", GetBoolValue(teamResp.Blocked, d.Get("blocked").(bool)))
+	team := &teamResp.TeamInfo
+	team.TeamMemberships = teamResp.TeamMemberships
+	team.RawMetadata = make(map[string]interface{}, len(team.Metadata))
+	for k, v := range team.Metadata {
+		team.RawMetadata[k] = v
+	}
 
-	log.Printf("[INFO] Successfully read team with ID: %s", d.Id())
-	return nil
+	if team.ModelAliases == nil && team.LiteLLMModelTable != nil {
+		team.ModelAliases = team.LiteLLMModelTable.ModelAliases
+	}
+
+	// The proxy stores per-model limits inside the team's metadata
+	for field, value := range map[string]*map[string]interface{}{
+		"model_rpm_limit":  &team.ModelRPMLimit,
+		"model_tpm_limit":  &team.ModelTPMLimit,
+		"model_max_budget": &team.ModelMaxBudget,
+	} {
+		if m, ok := team.Metadata[field].(map[string]interface{}); ok && *value == nil {
+			*value = m
+		}
+		delete(team.Metadata, field)
+	}
+
+	if s, ok := team.Metadata["blocked_reason"].(string); ok {
+		team.BlockedReason = s
+	}
+	delete(team.Metadata, "blocked_reason")
+
+	// The defaults for new team members are referenced from the metadata
+	if s, ok := team.Metadata["team_member_key_duration"].(string); ok {
+		team.TeamMemberKeyDuration = s
+	}
+	delete(team.Metadata, "team_member_key_duration")
+	for _, field := range preservedTeamMetadataFields {
+		delete(team.Metadata, field)
+	}
+
+	return team, nil
+}
+
+// preservedTeamMetadataFields are stored in the team's metadata by the proxy or
+// by other resources, and are carried over when litellm_team replaces the
+// metadata.
+var preservedTeamMetadataFields = []string{"callback_settings", "team_member_budget_id"}
+
+func isTeamNotFoundMessage(body string) bool {
+	return strings.Contains(strings.ToLower(body), "team not found") ||
+		strings.Contains(strings.ToLower(body), "team doesn't exist")
 }
 
 func resourceLiteLLMTeamUpdate(d *schema.ResourceData, m interface{}) error {
 	client := m.(*Client)
 
+	// The destroy behaviour is only stored in state
+	if !d.HasChangesExcept("on_destroy", "reassign_keys_to_team_id") {
+		return resourceLiteLLMTeamRead(d, m)
+	}
+
+	// Blocking and permissions have their own endpoints, so a change to only
+	// those doesn't send the rest of the team again
+	if d.HasChangesExcept("on_destroy", "reassign_keys_to_team_id", "blocked", "team_member_permissions") {
+		if err := updateTeamFields(client, d); err != nil {
+			return err
+		}
+	}
+
+	if d.HasChange("blocked") {
+		if err := setTeamBlocked(client, d.Id(), d.Get("blocked").(bool)); err != nil {
+			return err
+		}
+	}
+
+	if d.HasChange("team_member_permissions") {
+		if err := setTeamMemberPermissions(client, d.Id(), expandStringList(d.Get("team_member_permissions").(*schema.Set).List())); err != nil {
+			return err
+		}
+	}
+
+	log.Printf("[INFO] Successfully updated team with ID: %s", d.Id())
+	return resourceLiteLLMTeamRead(d, m)
+}
+
+// updateTeamFields sends the team's settings to /team/update.
+func updateTeamFields(client *Client, d *schema.ResourceData) error {
 	teamData := buildTeamData(d, d.Id())
-	log.Printf("[DEBUG] Update team request payload: %+v", teamData)
+
+	// Sending metadata replaces it, so keep what other resources stored there
+	if metadata, ok := teamData["metadata"].(map[string]interface{}); ok {
+		team, err := getTeamInfo(client, d.Id())
+		if err != nil {
+			return err
+		}
+		if team != nil {
+			merged := make(map[string]interface{}, len(metadata))
+			for k, v := range metadata {
+				merged[k] = v
+			}
+			for _, field := range preservedTeamMetadataFields {
+				if v, ok := team.RawMetadata[field]; ok {
+					merged[field] = v
+				}
+			}
+			teamData["metadata"] = merged
+		}
+	}
+	log.Printf("[DEBUG] Update team request payload: %s", redactForLog(teamData))
 
 	resp, err := MakeRequest(client, "POST", endpointTeamUpdate, teamData)
 	if err != nil {
@@ -155,12 +455,46 @@ func resourceLiteLLMTeamUpdate(d *schema.ResourceData, m interface{}) error {
 	}
 	defer resp.Body.Close()
 
-	if err := handleResponse(resp, "updating team"); err != nil {
+	return handleResponse(resp, "updating team")
+}
+
+// setTeamBlocked blocks or unblocks a team through the proxy's dedicated
+// endpoints, which /team/update doesn't reliably honour.
+func setTeamBlocked(client *Client, teamID string, blocked bool) error {
+	endpoint, action := endpointTeamUnblock, "unblocking team"
+	if blocked {
+		endpoint, action = endpointTeamBlock, "blocking team"
+	}
+
+	resp, err := MakeRequest(client, "POST", endpoint, map[string]interface{}{"team_id": teamID})
+	if err != nil {
+		return fmt.Errorf("error %s: %w", action, err)
+	}
+	defer resp.Body.Close()
+
+	if err := handleResponse(resp, action); err != nil {
 		return err
 	}
 
-	log.Printf("[INFO] Successfully updated team with ID: %s", d.Id())
-	return resourceLiteLLMTeamRead(d, m)
+	log.Printf("[INFO] Set blocked=%t for team %s", blocked, teamID)
+	return nil
+}
+
+// setTeamMemberPermissions replaces the permissions of a team's non-admin
+// members.
+func setTeamMemberPermissions(client *Client, teamID string, permissions []string) error {
+	permissionsData := map[string]interface{}{
+		"team_id":                 teamID,
+		"team_member_permissions": permissions,
+	}
+
+	resp, err := MakeRequest(client, "POST", endpointTeamPermissionsUpdate, permissionsData)
+	if err != nil {
+		return fmt.Errorf("error updating team permissions: %w", err)
+	}
+	defer resp.Body.Close()
+
+	return handleResponse(resp, "updating team permissions")
 }
 
 func resourceLiteLLMTeamDelete(d *schema.ResourceData, m interface{}) error {
@@ -168,6 +502,10 @@ func resourceLiteLLMTeamDelete(d *schema.ResourceData, m interface{}) error {
 
 	log.Printf("[INFO] Deleting team with ID: %s", d.Id())
 
+	if err := releaseTeamKeys(client, d.Id(), d.Get("on_destroy").(string), d.Get("reassign_keys_to_team_id").(string)); err != nil {
+		return err
+	}
+
 	deleteData := map[string]interface{}{
 		"team_ids": []string{d.Id()},
 	}
@@ -187,16 +525,109 @@ func resourceLiteLLMTeamDelete(d *schema.ResourceData, m interface{}) error {
 	return nil
 }
 
+// releaseTeamKeys deletes or reassigns the keys a team owns before the team is
+// deleted. With on_destroy "fail", it refuses to continue if the team still
+// owns keys, since depending on the version the proxy either rejects the
+// deletion or leaves the keys behind without a team.
+func releaseTeamKeys(client *Client, teamID, onDestroy, targetTeamID string) error {
+	if onDestroy == "" {
+		return nil
+	}
+
+	keys, err := client.ListTeamKeys(teamID)
+	if err != nil {
+		return fmt.Errorf("error listing keys of team %s: %w", teamID, err)
+	}
+	if len(keys) == 0 {
+		return nil
+	}
+
+	switch onDestroy {
+	case teamOnDestroyDeleteKeys:
+		for _, key := range keys {
+			if err := client.DeleteKey(key.Token); err != nil {
+				return fmt.Errorf("error deleting key %s of team %s: %w", describeKey(key), teamID, err)
+			}
+		}
+		log.Printf("[INFO] Deleted %d keys of team %s", len(keys), teamID)
+	case teamOnDestroyReassignKeys:
+		for _, key := range keys {
+			if err := client.MoveKeyToTeam(key.Token, targetTeamID); err != nil {
+				return fmt.Errorf("error moving key %s of team %s to team %s: %w", describeKey(key), teamID, targetTeamID, err)
+			}
+		}
+		log.Printf("[INFO] Moved %d keys of team %s to team %s", len(keys), teamID, targetTeamID)
+	default:
+		names := make([]string, len(keys))
+		for i, key := range keys {
+			names[i] = describeKey(key)
+		}
+		return fmt.Errorf("team %s still owns %d keys: %s; delete them first or set on_destroy to %q or %q",
+			teamID, len(keys), strings.Join(names, ", "), teamOnDestroyDeleteKeys, teamOnDestroyReassignKeys)
+	}
+
+	return nil
+}
+
+// describeKey identifies a key by its alias if it has one, and its hashed
+// token otherwise.
+func describeKey(key *Key) string {
+	if key.KeyAlias != "" {
+		return fmt.Sprintf("%s (%s)", key.KeyAlias, key.Token)
+	}
+	return key.Token
+}
+
 func buildTeamData(d *schema.ResourceData, teamID string) map[string]interface{} {
 	teamData := map[string]interface{}{
 		"team_id":    teamID,
 		"team_alias": d.Get("team_alias").(string),
 	}
 
-	for _, key := range []string{"organization_id", "metadata", "tpm_limit", "rpm_limit", "max_budget", "budget_duration", "models", "blocked"} {
-		if v, ok := d.GetOk(key); ok {
-			teamData[key] = v
-		}
+	// The reason a team is blocked is kept in its metadata. Removed metadata
+	// is sent as an empty map to clear it, along with the provider-managed
+	// fields that Update carries over.
+	metadata := getMetadata(d)
+	if v, ok := d.GetOk("blocked_reason"); ok {
+		metadata["blocked_reason"] = v.(string)
+	}
+	if len(metadata) > 0 || d.HasChanges("metadata", "metadata_json", "blocked_reason") {
+		teamData["metadata"] = metadata
+	}
+
+	// Per-model settings are always sent, so that removing them clears them
+	teamData["model_aliases"] = d.Get("model_aliases").(map[string]interface{})
+	teamData["model_rpm_limit"] = d.Get("model_rpm_limit").(map[string]interface{})
+	teamData["model_tpm_limit"] = d.Get("model_tpm_limit").(map[string]interface{})
+	teamData["model_max_budget"] = expandModelBudgets(d.Get("model_budget").(*schema.Set).List())
+
+	if v := getOptionalFloat(d, "team_member_budget"); v != nil {
+		teamData["team_member_budget"] = *v
+	}
+	if v := getOptionalInt(d, "team_member_rpm_limit"); v != nil {
+		teamData["team_member_rpm_limit"] = *v
+	}
+	if v := getOptionalInt(d, "team_member_tpm_limit"); v != nil {
+		teamData["team_member_tpm_limit"] = *v
+	}
+	if v, ok := d.GetOk("team_member_key_duration"); ok {
+		teamData["team_member_key_duration"] = v.(string)
+	}
+
+	// Unset limits are sent as null, so that removing them clears them
+	teamData["tpm_limit"] = getOptionalInt(d, "tpm_limit")
+	teamData["rpm_limit"] = getOptionalInt(d, "rpm_limit")
+	teamData["max_budget"] = getOptionalFloat(d, "max_budget")
+	teamData["budget_duration"] = nullIfEmpty(d.Get("budget_duration").(string))
+
+	if v, ok := d.GetOk("organization_id"); ok {
+		teamData["organization_id"] = v
+	}
+
+	// models is computed when it isn't configured, so that grants made by
+	// litellm_team_model aren't sent back and overwritten
+	if !isNullInConfig(d, "models") && d.HasChange("models") {
+		teamData["models"] = d.Get("models")
 	}
 
 	return teamData
@@ -0,0 +1,290 @@
+package litellm
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+// knownTeamCallbacks are the logging integrations LiteLLM can configure per team.
+var knownTeamCallbacks = []string{
+	"langfuse",
+	"langsmith",
+	"datadog",
+	"braintrust",
+	"arize",
+	"opik",
+	"lago",
+	"openmeter",
+	"lunary",
+	"helicone",
+	"mlflow",
+	"gcs_bucket",
+	"s3",
+	"otel",
+	"generic_api",
+}
+
+// teamCallbackResponse is the response of GET /team/{team_id}/callback.
+type teamCallbackResponse struct {
+	Data struct {
+		SuccessCallbacks []string          `json:"success_callbacks"`
+		FailureCallbacks []string          `json:"failure_callbacks"`
+		CallbackVars     map[string]string `json:"callback_vars"`
+	} `json:"data"`
+}
+
+func resourceLiteLLMTeamCallback() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceLiteLLMTeamCallbackCreate,
+		Read:   resourceLiteLLMTeamCallbackRead,
+		Update: resourceLiteLLMTeamCallbackUpdate,
+		Delete: resourceLiteLLMTeamCallbackDelete,
+
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceLiteLLMTeamCallbackImport,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"team_id": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			"callback_name": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.StringInSlice(knownTeamCallbacks, false),
+			},
+			"callback_type": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Default:  "both",
+				ValidateFunc: validation.StringInSlice([]string{
+					"success",
+					"failure",
+					"both",
+				}, false),
+			},
+			"callback_vars": {
+				Type:      schema.TypeMap,
+				Required:  true,
+				Sensitive: true,
+				Elem:      &schema.Schema{Type: schema.TypeString},
+			},
+		},
+	}
+}
+
+func resourceLiteLLMTeamCallbackCreate(d *schema.ResourceData, m interface{}) error {
+	client := m.(*Client)
+
+	teamID := d.Get("team_id").(string)
+	callbackName := d.Get("callback_name").(string)
+
+	if err := addTeamCallback(client, teamID, callbackName, d.Get("callback_type").(string), d.Get("callback_vars").(map[string]interface{})); err != nil {
+		return err
+	}
+
+	d.SetId(fmt.Sprintf("%s:%s", teamID, callbackName))
+	log.Printf("[INFO] Added callback %s to team %s", callbackName, teamID)
+
+	return resourceLiteLLMTeamCallbackRead(d, m)
+}
+
+func resourceLiteLLMTeamCallbackRead(d *schema.ResourceData, m interface{}) error {
+	client := m.(*Client)
+
+	teamID := d.Get("team_id").(string)
+	callbackName := d.Get("callback_name").(string)
+
+	log.Printf("[INFO] Reading callback %s of team %s", callbackName, teamID)
+
+	resp, err := MakeRequest(client, "GET", fmt.Sprintf("/team/%s/callback", url.PathEscape(teamID)), nil)
+	if err != nil {
+		return fmt.Errorf("error reading team callback: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("error reading team callback response: %w", err)
+	}
+	if resp.StatusCode == http.StatusNotFound || (resp.StatusCode != http.StatusOK && isTeamNotFoundMessage(string(body))) {
+		log.Printf("[WARN] Team %s not found, removing callback from state", teamID)
+		d.SetId("")
+		return nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("error reading team callback: %s - %s", resp.Status, string(body))
+	}
+
+	var callbackResp teamCallbackResponse
+	if err := json.Unmarshal(body, &callbackResp); err != nil {
+		return fmt.Errorf("error decoding team callback response: %w", err)
+	}
+
+	success := containsString(callbackResp.Data.SuccessCallbacks, callbackName)
+	failure := containsString(callbackResp.Data.FailureCallbacks, callbackName)
+	switch {
+	case success && failure:
+		d.Set("callback_type", "both")
+	case success:
+		d.Set("callback_type", "success")
+	case failure:
+		d.Set("callback_type", "failure")
+	default:
+		// Variables left behind by a callback that is no longer listed are
+		// ignored, and replaced when the callback is added again
+		log.Printf("[WARN] Callback %s not found in team %s, removing from state", callbackName, teamID)
+		d.SetId("")
+		return nil
+	}
+
+	// The proxy keeps the variables of all of a team's callbacks in one map,
+	// so only the ones this resource manages are read back. On import, the
+	// variables prefixed with the callback name are taken.
+	managed := d.Get("callback_vars").(map[string]interface{})
+	callbackVars := make(map[string]string)
+	for k, v := range callbackResp.Data.CallbackVars {
+		if _, ok := managed[k]; ok || (len(managed) == 0 && strings.HasPrefix(strings.ToLower(k), callbackName+"_")) {
+			callbackVars[k] = v
+		}
+	}
+	d.Set("callback_vars", callbackVars)
+
+	return nil
+}
+
+func resourceLiteLLMTeamCallbackUpdate(d *schema.ResourceData, m interface{}) error {
+	client := m.(*Client)
+
+	teamID := d.Get("team_id").(string)
+	callbackName := d.Get("callback_name").(string)
+
+	// The proxy refuses to add a callback that already exists, so the old
+	// configuration is removed first
+	oldType, _ := d.GetChange("callback_type")
+	oldVars, _ := d.GetChange("callback_vars")
+	if err := removeTeamCallback(client, teamID, callbackName, oldVars.(map[string]interface{})); err != nil {
+		return err
+	}
+	if err := addTeamCallback(client, teamID, callbackName, d.Get("callback_type").(string), d.Get("callback_vars").(map[string]interface{})); err != nil {
+		// Put the old configuration back, so that state still describes
+		// the callback. If that fails too, the callback is gone.
+		d.Partial(true)
+		if restoreErr := addTeamCallback(client, teamID, callbackName, oldType.(string), oldVars.(map[string]interface{})); restoreErr != nil {
+			log.Printf("[WARN] Could not restore callback %s of team %s, removing from state: %s", callbackName, teamID, restoreErr)
+			d.SetId("")
+		}
+		return err
+	}
+
+	log.Printf("[INFO] Updated callback %s of team %s", callbackName, teamID)
+	return resourceLiteLLMTeamCallbackRead(d, m)
+}
+
+func resourceLiteLLMTeamCallbackDelete(d *schema.ResourceData, m interface{}) error {
+	client := m.(*Client)
+
+	teamID := d.Get("team_id").(string)
+	callbackName := d.Get("callback_name").(string)
+
+	if err := removeTeamCallback(client, teamID, callbackName, d.Get("callback_vars").(map[string]interface{})); err != nil {
+		return err
+	}
+
+	log.Printf("[INFO] Removed callback %s from team %s", callbackName, teamID)
+	d.SetId("")
+	return nil
+}
+
+func resourceLiteLLMTeamCallbackImport(ctx context.Context, d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+	teamID, callbackName, ok := strings.Cut(d.Id(), ":")
+	if !ok || teamID == "" || callbackName == "" {
+		return nil, fmt.Errorf("invalid import ID %q, expected <team_id>:<callback_name>", d.Id())
+	}
+
+	d.Set("team_id", teamID)
+	d.Set("callback_name", callbackName)
+	return []*schema.ResourceData{d}, nil
+}
+
+func addTeamCallback(client *Client, teamID, callbackName, callbackType string, callbackVars map[string]interface{}) error {
+	if callbackType == "both" {
+		callbackType = "success_and_failure"
+	}
+
+	callbackData := map[string]interface{}{
+		"callback_name": callbackName,
+		"callback_type": callbackType,
+		"callback_vars": callbackVars,
+	}
+
+	resp, err := MakeRequest(client, "POST", fmt.Sprintf("/team/%s/callback", url.PathEscape(teamID)), callbackData)
+	if err != nil {
+		return fmt.Errorf("error adding team callback: %w", err)
+	}
+	defer resp.Body.Close()
+
+	return handleResponse(resp, "adding team callback")
+}
+
+// removeTeamCallback removes a single callback and its variables from the
+// team's callback settings. The proxy only has an endpoint that disables all
+// of a team's callbacks, so the settings stored in the team's metadata are
+// edited instead.
+func removeTeamCallback(client *Client, teamID, callbackName string, callbackVars map[string]interface{}) error {
+	team, err := getTeamInfo(client, teamID)
+	if err != nil {
+		return err
+	}
+	if team == nil {
+		return nil
+	}
+
+	settings, _ := team.RawMetadata["callback_settings"].(map[string]interface{})
+	if settings == nil {
+		return nil
+	}
+
+	for _, field := range []string{"success_callback", "failure_callback"} {
+		callbacks := expandInterfaceStringList(settings[field])
+		remaining := make([]string, 0, len(callbacks))
+		for _, c := range callbacks {
+			if c != callbackName {
+				remaining = append(remaining, c)
+			}
+		}
+		settings[field] = remaining
+	}
+	if vars, ok := settings["callback_vars"].(map[string]interface{}); ok {
+		for k := range callbackVars {
+			delete(vars, k)
+		}
+	}
+
+	metadata := team.RawMetadata
+	metadata["callback_settings"] = settings
+	teamData := map[string]interface{}{
+		"team_id":  teamID,
+		"metadata": metadata,
+	}
+
+	resp, err := MakeRequest(client, "POST", endpointTeamUpdate, teamData)
+	if err != nil {
+		return fmt.Errorf("error removing team callback: %w", err)
+	}
+	defer resp.Body.Close()
+
+	return handleResponse(resp, "removing team callback")
+}
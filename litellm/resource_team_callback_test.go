@@ -0,0 +1,205 @@
+package litellm
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func testTeamCallbackConfig(teamID string) map[string]interface{} {
+	return map[string]interface{}{
+		"team_id":       teamID,
+		"callback_name": "langfuse",
+		"callback_vars": map[string]interface{}{
+			"langfuse_public_key": "pk-1",
+			"langfuse_secret_key": "sk-1",
+		},
+	}
+}
+
+// callbackSettings returns the callback settings stored with the team.
+func callbackSettings(proxy *fakeProxy, teamID string) map[string]interface{} {
+	return teamCallbackSettings(proxy.teams[teamID])
+}
+
+func TestResourceTeamCallback(t *testing.T) {
+	proxy := newFakeProxy(t)
+	client := proxy.client()
+	r := resourceLiteLLMTeamCallback()
+
+	teamID := createTestTeam(t, proxy)
+	applyResource(t, r, nil, mergeMaps(testTeamCallbackConfig(teamID), map[string]interface{}{
+		"callback_name": "datadog",
+		"callback_type": "failure",
+		"callback_vars": map[string]interface{}{"datadog_api_key": "dd-1"},
+	}), client)
+
+	config := testTeamCallbackConfig(teamID)
+	state := applyResource(t, r, nil, config, client)
+	if state.ID != teamID+":langfuse" {
+		t.Errorf("expected ID %s:langfuse, got %s", teamID, state.ID)
+	}
+	settings := callbackSettings(proxy, teamID)
+	if !reflect.DeepEqual(settings["success_callback"], []interface{}{"langfuse"}) || !reflect.DeepEqual(settings["failure_callback"], []interface{}{"datadog", "langfuse"}) {
+		t.Errorf("expected langfuse to log successes and failures, got %v", settings)
+	}
+	state = refreshResource(t, r, state, client)
+	assertNoDiff(t, r, state, config, client)
+
+	updated := mergeMaps(config, map[string]interface{}{
+		"callback_type": "success",
+		"callback_vars": map[string]interface{}{
+			"langfuse_public_key": "pk-2",
+			"langfuse_secret_key": "sk-2",
+		},
+	})
+	state = applyResource(t, r, state, updated, client)
+	settings = callbackSettings(proxy, teamID)
+	if !reflect.DeepEqual(settings["failure_callback"], []interface{}{"datadog"}) {
+		t.Errorf("expected langfuse to stop logging failures, got %v", settings["failure_callback"])
+	}
+	if vars := settings["callback_vars"].(map[string]interface{}); vars["langfuse_secret_key"] != "sk-2" || vars["datadog_api_key"] != "dd-1" {
+		t.Errorf("expected only langfuse's variables to change, got %v", vars)
+	}
+	state = refreshResource(t, r, state, client)
+	assertNoDiff(t, r, state, updated, client)
+
+	imported, err := r.Importer.StateContext(context.Background(), r.Data(&terraform.InstanceState{ID: teamID + ":langfuse"}), client)
+	if err != nil {
+		t.Fatalf("error importing: %s", err)
+	}
+	importedState := refreshResource(t, r, imported[0].State(), client)
+	assertNoDiff(t, r, importedState, updated, client)
+	if _, err := r.Importer.StateContext(context.Background(), r.Data(&terraform.InstanceState{ID: "langfuse"}), client); err == nil {
+		t.Error("expected an error for an import ID without a team ID")
+	}
+
+	if err := destroyResource(t, r, state, client); err != nil {
+		t.Fatalf("error destroying: %s", err)
+	}
+	settings = callbackSettings(proxy, teamID)
+	if !reflect.DeepEqual(settings["success_callback"], []interface{}{}) || !reflect.DeepEqual(settings["failure_callback"], []interface{}{"datadog"}) {
+		t.Errorf("expected only langfuse to be removed, got %v", settings)
+	}
+	if vars := settings["callback_vars"]; !reflect.DeepEqual(vars, map[string]interface{}{"datadog_api_key": "dd-1"}) {
+		t.Errorf("expected only langfuse's variables to be removed, got %v", vars)
+	}
+}
+
+func TestResourceTeamCallbackRemovedOutsideTerraform(t *testing.T) {
+	proxy := newFakeProxy(t)
+	client := proxy.client()
+	r := resourceLiteLLMTeamCallback()
+
+	teamID := createTestTeam(t, proxy)
+	state := applyResource(t, r, nil, testTeamCallbackConfig(teamID), client)
+
+	// Variables left behind don't count as the callback being configured
+	settings := callbackSettings(proxy, teamID)
+	settings["success_callback"] = []interface{}{}
+	settings["failure_callback"] = []interface{}{}
+	if state := refreshResource(t, r, state, client); state != nil && state.ID != "" {
+		t.Errorf("expected the removed callback to be removed from state, got %v", state)
+	}
+	applyResource(t, r, nil, testTeamCallbackConfig(teamID), client)
+
+	delete(proxy.teams, teamID)
+	if state := refreshResource(t, r, state, client); state != nil && state.ID != "" {
+		t.Errorf("expected the callback of a deleted team to be removed from state, got %v", state)
+	}
+	if err := destroyResource(t, r, state, client); err != nil {
+		t.Errorf("expected destroying the callback of a deleted team to succeed, got %s", err)
+	}
+}
+
+func TestResourceTeamCallbackUpdateFailure(t *testing.T) {
+	proxy := newFakeProxy(t)
+	client := proxy.client()
+	r := resourceLiteLLMTeamCallback()
+
+	teamID := createTestTeam(t, proxy)
+	config := testTeamCallbackConfig(teamID)
+	state := applyResource(t, r, nil, config, client)
+	updated := mergeMaps(config, map[string]interface{}{"callback_type": "failure"})
+
+	// A rejected update puts the previous configuration back
+	proxy.failNext["/team/"+teamID+"/callback"] = 1
+	newState, diags := r.Apply(context.Background(), state, planResource(t, r, state, updated, client), client)
+	if !diags.HasError() {
+		t.Fatal("expected the update to fail")
+	}
+	if newState == nil || newState.ID != state.ID || newState.Attributes["callback_type"] != "both" {
+		t.Errorf("expected state to keep the previous configuration, got %v", newState)
+	}
+	settings := callbackSettings(proxy, teamID)
+	if !reflect.DeepEqual(settings["success_callback"], []interface{}{"langfuse"}) || !reflect.DeepEqual(settings["failure_callback"], []interface{}{"langfuse"}) {
+		t.Errorf("expected the previous configuration to be restored, got %v", settings)
+	}
+
+	// If that fails too, the callback is removed from state
+	proxy.failNext["/team/"+teamID+"/callback"] = 2
+	newState, diags = r.Apply(context.Background(), state, planResource(t, r, state, updated, client), client)
+	if !diags.HasError() {
+		t.Fatal("expected the update to fail")
+	}
+	if newState != nil && newState.ID != "" {
+		t.Errorf("expected the lost callback to be removed from state, got %v", newState)
+	}
+}
+
+func TestResourceTeamUpdateKeepsCallbacks(t *testing.T) {
+	proxy := newFakeProxy(t)
+	client := proxy.client()
+	team := ResourceLiteLLMTeam()
+	r := resourceLiteLLMTeamCallback()
+
+	teamConfig := mergeMaps(testTeamConfig(), map[string]interface{}{
+		"metadata": map[string]interface{}{"cost_center": "r-and-d"},
+	})
+	teamState := applyResource(t, team, nil, teamConfig, client)
+	config := testTeamCallbackConfig(teamState.ID)
+	state := applyResource(t, r, nil, config, client)
+
+	teamState = refreshResource(t, team, teamState, client)
+	assertNoDiff(t, team, teamState, teamConfig, client)
+
+	applyResource(t, team, teamState, mergeMaps(teamConfig, map[string]interface{}{
+		"metadata": map[string]interface{}{"cost_center": "platform"},
+	}), client)
+	if sent, _ := proxy.lastRequest("/team/update")["metadata"].(map[string]interface{}); sent["callback_settings"] == nil {
+		t.Errorf("expected the team update to send the callback settings back, got %v", sent)
+	}
+	state = refreshResource(t, r, state, client)
+	if state == nil || state.ID == "" {
+		t.Fatal("expected the callback to survive the team update")
+	}
+	assertNoDiff(t, r, state, config, client)
+}
+
+func TestResourceTeamCallbackVarsNotLogged(t *testing.T) {
+	proxy := newFakeProxy(t)
+	client := proxy.client()
+	r := resourceLiteLLMTeamCallback()
+	logs := captureLogs(t)
+
+	// Updating the team sends the callback settings back in its metadata
+	team := ResourceLiteLLMTeam()
+	teamConfig := mergeMaps(testTeamConfig(), map[string]interface{}{
+		"metadata": map[string]interface{}{"cost_center": "r-and-d"},
+	})
+	teamState := applyResource(t, team, nil, teamConfig, client)
+	config := testTeamCallbackConfig(teamState.ID)
+	config["callback_vars"] = map[string]interface{}{"langfuse_secret_key": "lf-secret-value"}
+	state := applyResource(t, r, nil, config, client)
+	applyResource(t, team, teamState, mergeMaps(teamConfig, map[string]interface{}{
+		"metadata": map[string]interface{}{"cost_center": "platform"},
+	}), client)
+	refreshResource(t, r, state, client)
+
+	if strings.Contains(logs.String(), "lf-secret-value") {
+		t.Error("expected callback variables not to be logged")
+	}
+}
@@ -1,13 +1,20 @@
 package litellm
 
 import (
+	"context"
 	"fmt"
 	"log"
+	"strings"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 )
 
+const endpointTeamMemberUpdate = "/team/member_update"
+
+// teamMemberRoles are the roles the team member endpoints accept.
+var teamMemberRoles = []string{"admin", "user"}
+
 func resourceLiteLLMTeamMember() *schema.Resource {
 	return &schema.Resource{
 		Create: resourceLiteLLMTeamMemberCreate,
@@ -15,33 +22,46 @@ func resourceLiteLLMTeamMember() *schema.Resource {
 		Update: resourceLiteLLMTeamMemberUpdate,
 		Delete: resourceLiteLLMTeamMemberDelete,
 
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceLiteLLMTeamMemberImport,
+		},
+
 		Schema: map[string]*schema.Schema{
 			"team_id": {
 				Type:     schema.TypeString,
 				Required: true,
+				ForceNew: true,
 			},
 			"user_id": {
 				Type:     schema.TypeString,
 				Required: true,
+				ForceNew: true,
 			},
 			"user_email": {
 				Type:     schema.TypeString,
 				Required: true,
 			},
 			"role": {
-				Type:     schema.TypeString,
-				Required: true,
-				ValidateFunc: validation.StringInSlice([]string{
-					"org_admin",
-					"internal_user",
-					"internal_user_viewer",
-					"admin",
-					"user",
-				}, false),
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: validation.StringInSlice(teamMemberRoles, false),
 			},
+			// The proxy can't clear a member's limits, so removing them
+			// keeps the current values
 			"max_budget_in_team": {
 				Type:     schema.TypeFloat,
 				Optional: true,
+				Computed: true,
+			},
+			"tpm_limit": {
+				Type:     schema.TypeInt,
+				Optional: true,
+				Computed: true,
+			},
+			"rpm_limit": {
+				Type:     schema.TypeInt,
+				Optional: true,
+				Computed: true,
 			},
 		},
 	}
@@ -61,8 +81,8 @@ func resourceLiteLLMTeamMemberCreate(d *schema.ResourceData, m interface{}) erro
 		"team_id": d.Get("team_id").(string),
 	}
 
-	if v, ok := d.GetOk("max_budget_in_team"); ok {
-		memberData["max_budget_in_team"] = v.(float64)
+	if v := getOptionalFloat(d, "max_budget_in_team"); v != nil {
+		memberData["max_budget_in_team"] = *v
 	}
 
 	log.Printf("[DEBUG] Create team member request payload: %+v", memberData)
@@ -82,14 +102,87 @@ func resourceLiteLLMTeamMemberCreate(d *schema.ResourceData, m interface{}) erro
 
 	log.Printf("[INFO] Team member created with ID: %s", d.Id())
 
+	// Rate limits can't be set when adding a member
+	tpmLimit, rpmLimit := getOptionalInt(d, "tpm_limit"), getOptionalInt(d, "rpm_limit")
+	if tpmLimit != nil || rpmLimit != nil {
+		updateData := map[string]interface{}{
+			"user_id": d.Get("user_id").(string),
+			"team_id": d.Get("team_id").(string),
+		}
+		if tpmLimit != nil {
+			updateData["tpm_limit"] = *tpmLimit
+		}
+		if rpmLimit != nil {
+			updateData["rpm_limit"] = *rpmLimit
+		}
+		if err := updateTeamMember(client, updateData); err != nil {
+			return err
+		}
+	}
+
 	return resourceLiteLLMTeamMemberRead(d, m)
 }
 
 func resourceLiteLLMTeamMemberRead(d *schema.ResourceData, m interface{}) error {
-	// There's no specific endpoint to read a single team member
-	// We might need to read the entire team and find the member
-	// For now, we'll just return the data we have in the state
+	client := m.(*Client)
+
 	log.Printf("[INFO] Reading team member with ID: %s", d.Id())
+
+	// There's no endpoint to read a single team member, so the member is
+	// looked up in the team
+	teamID := d.Get("team_id").(string)
+	team, err := getTeamInfo(client, teamID)
+	if err != nil {
+		return err
+	}
+	if team == nil {
+		log.Printf("[WARN] Team %s not found, removing team member %s from state", teamID, d.Id())
+		d.SetId("")
+		return nil
+	}
+
+	member := findTeamMember(team, d.Get("user_id").(string), d.Get("user_email").(string))
+	if member == nil {
+		log.Printf("[WARN] Team member %s not found, removing from state", d.Id())
+		d.SetId("")
+		return nil
+	}
+
+	if member.UserEmail != "" {
+		d.Set("user_email", member.UserEmail)
+	}
+	d.Set("role", member.Role)
+
+	// The member's limits are only tracked once one was set
+	var maxBudget *float64
+	var tpmLimit, rpmLimit *int
+	for _, membership := range team.TeamMemberships {
+		if membership.UserID == member.UserID && membership.LiteLLMBudgetTable != nil {
+			maxBudget = membership.LiteLLMBudgetTable.MaxBudget
+			tpmLimit = membership.LiteLLMBudgetTable.TPMLimit
+			rpmLimit = membership.LiteLLMBudgetTable.RPMLimit
+		}
+	}
+	d.Set("max_budget_in_team", maxBudget)
+	d.Set("tpm_limit", tpmLimit)
+	d.Set("rpm_limit", rpmLimit)
+
+	return nil
+}
+
+// findTeamMember looks up a member of the team by user ID, falling back to
+// the email for members the proxy stored under a different user ID.
+func findTeamMember(team *TeamResponse, userID, userEmail string) *TeamMember {
+	for i, member := range team.MembersWithRoles {
+		if userID != "" && member.UserID == userID {
+			return &team.MembersWithRoles[i]
+		}
+	}
+	for i, member := range team.MembersWithRoles {
+		if userEmail != "" && strings.EqualFold(member.UserEmail, userEmail) {
+			return &team.MembersWithRoles[i]
+		}
+	}
 	return nil
 }
 
@@ -100,27 +193,51 @@ func resourceLiteLLMTeamMemberUpdate(d *schema.ResourceData, m interface{}) erro
 		"user_id":    d.Get("user_id").(string),
 		"user_email": d.Get("user_email").(string),
 		"team_id":    d.Get("team_id").(string),
+		"role":       d.Get("role").(string),
+	}
+
+	if v := getOptionalFloat(d, "max_budget_in_team"); v != nil {
+		updateData["max_budget_in_team"] = *v
+	}
+	if v := getOptionalInt(d, "tpm_limit"); v != nil {
+		updateData["tpm_limit"] = *v
+	}
+	if v := getOptionalInt(d, "rpm_limit"); v != nil {
+		updateData["rpm_limit"] = *v
 	}
 
-	if v, ok := d.GetOk("max_budget_in_team"); ok {
-		updateData["max_budget_in_team"] = v.(float64)
+	if err := updateTeamMember(client, updateData); err != nil {
+		return err
 	}
 
+	log.Printf("[INFO] Successfully updated team member with ID: %s", d.Id())
+
+	return resourceLiteLLMTeamMemberRead(d, m)
+}
+
+// updateTeamMember changes a member's role or limits within a team. The
+// member is identified by the user_id or user_email in updateData.
+func updateTeamMember(client *Client, updateData map[string]interface{}) error {
 	log.Printf("[DEBUG] Update team member request payload: %+v", updateData)
 
-	resp, err := MakeRequest(client, "POST", "/team/member_update", updateData)
+	resp, err := MakeRequest(client, "POST", endpointTeamMemberUpdate, updateData)
 	if err != nil {
 		return fmt.Errorf("error updating team member: %v", err)
 	}
 	defer resp.Body.Close()
 
-	if err := handleResponse(resp, "updating team member"); err != nil {
-		return err
-	}
+	return handleResponse(resp, "updating team member")
+}
 
-	log.Printf("[INFO] Successfully updated team member with ID: %s", d.Id())
+func resourceLiteLLMTeamMemberImport(ctx context.Context, d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+	teamID, userID, ok := strings.Cut(d.Id(), ":")
+	if !ok || teamID == "" || userID == "" {
+		return nil, fmt.Errorf("invalid import ID %q, expected <team_id>:<user_id>", d.Id())
+	}
 
-	return resourceLiteLLMTeamMemberRead(d, m)
+	d.Set("team_id", teamID)
+	d.Set("user_id", userID)
+	return []*schema.ResourceData{d}, nil
 }
 
 func resourceLiteLLMTeamMemberDelete(d *schema.ResourceData, m interface{}) error {
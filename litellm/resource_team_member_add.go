@@ -3,6 +3,7 @@ package litellm
 import (
 	"fmt"
 	"log"
+	"strings"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
@@ -35,12 +36,21 @@ func resourceLiteLLMTeamMemberAdd() *schema.Resource {
 							Optional: true,
 						},
 						"role": {
-							Type:     schema.TypeString,
-							Required: true,
-							ValidateFunc: validation.StringInSlice([]string{
-								"admin",
-								"user",
-							}, false),
+							Type:         schema.TypeString,
+							Required:     true,
+							ValidateFunc: validation.StringInSlice(teamMemberRoles, false),
+						},
+						"max_budget_in_team": {
+							Type:     schema.TypeFloat,
+							Optional: true,
+						},
+						"tpm_limit": {
+							Type:     schema.TypeInt,
+							Optional: true,
+						},
+						"rpm_limit": {
+							Type:     schema.TypeInt,
+							Optional: true,
 						},
 					},
 				},
@@ -57,42 +67,9 @@ func resourceLiteLLMTeamMemberAddCreate(d *schema.ResourceData, m interface{}) e
 	client := m.(*Client)
 
 	teamID := d.Get("team_id").(string)
-	members := d.Get("member").(*schema.Set)
-
-	// Convert members to the expected format
-	membersList := make([]map[string]interface{}, 0, members.Len())
-	for _, member := range members.List() {
-		m := member.(map[string]interface{})
-		memberData := map[string]interface{}{
-			"role": m["role"].(string),
-		}
-		if userID, ok := m["user_id"].(string); ok && userID != "" {
-			memberData["user_id"] = userID
-		}
-		if userEmail, ok := m["user_email"].(string); ok && userEmail != "" {
-			memberData["user_email"] = userEmail
-		}
-		membersList = append(membersList, memberData)
-	}
-
-	memberData := map[string]interface{}{
-		"member":  membersList,
-		"team_id": teamID,
-	}
-
-	if v, ok := d.GetOk("max_budget_in_team"); ok {
-		memberData["max_budget_in_team"] = v.(float64)
-	}
-
-	log.Printf("[DEBUG] Create team members request payload: %+v", memberData)
+	members := d.Get("member").(*schema.Set).List()
 
-	resp, err := MakeRequest(client, "POST", "/team/member_add", memberData)
-	if err != nil {
-		return fmt.Errorf("error adding team members: %v", err)
-	}
-	defer resp.Body.Close()
-
-	if err := handleResponse(resp, "adding team members"); err != nil {
+	if err := addTeamMembers(client, d, teamID, members); err != nil {
 		return err
 	}
 
@@ -113,22 +90,16 @@ func resourceLiteLLMTeamMemberAddUpdate(d *schema.ResourceData, m interface{}) e
 	teamID := d.Get("team_id").(string)
 
 	o, n := d.GetChange("member")
-	oldMembers := o.(*schema.Set)
-	newMembers := n.(*schema.Set)
+	oldMembers := teamMembersByIdentity(o.(*schema.Set).List())
+	newMembers := teamMembersByIdentity(n.(*schema.Set).List())
 
 	// Find members to remove (in old but not in new)
-	for _, member := range oldMembers.Difference(newMembers).List() {
-		m := member.(map[string]interface{})
-		deleteData := map[string]interface{}{
-			"team_id": teamID,
-		}
-		if userID, ok := m["user_id"].(string); ok && userID != "" {
-			deleteData["user_id"] = userID
-		}
-		if userEmail, ok := m["user_email"].(string); ok && userEmail != "" {
-			deleteData["user_email"] = userEmail
+	for identity, member := range oldMembers {
+		if _, ok := newMembers[identity]; ok {
+			continue
 		}
 
+		deleteData := teamMemberRef(teamID, member)
 		resp, err := MakeRequest(client, "POST", "/team/member_delete", deleteData)
 		if err != nil {
 			return fmt.Errorf("error deleting team member: %v", err)
@@ -140,42 +111,46 @@ func resourceLiteLLMTeamMemberAddUpdate(d *schema.ResourceData, m interface{}) e
 		}
 	}
 
-	// Find members to add (in new but not in old)
-	membersToAdd := newMembers.Difference(oldMembers).List()
-	if len(membersToAdd) > 0 {
-		membersList := make([]map[string]interface{}, 0, len(membersToAdd))
-		for _, member := range membersToAdd {
-			m := member.(map[string]interface{})
-			memberData := map[string]interface{}{
-				"role": m["role"].(string),
-			}
-			if userID, ok := m["user_id"].(string); ok && userID != "" {
-				memberData["user_id"] = userID
-			}
-			if userEmail, ok := m["user_email"].(string); ok && userEmail != "" {
-				memberData["user_email"] = userEmail
-			}
-			membersList = append(membersList, memberData)
+	// Members that stay in the team are updated in place, so that they keep
+	// their spend within the team
+	var membersToAdd []interface{}
+	for identity, member := range newMembers {
+		old, ok := oldMembers[identity]
+		if !ok {
+			membersToAdd = append(membersToAdd, member)
+			continue
 		}
 
-		memberData := map[string]interface{}{
-			"member":  membersList,
-			"team_id": teamID,
+		updateData := teamMemberRef(teamID, member)
+		changed := false
+		if member["role"] != old["role"] {
+			updateData["role"] = member["role"]
+			changed = true
 		}
-
-		if v, ok := d.GetOk("max_budget_in_team"); ok {
-			memberData["max_budget_in_team"] = v.(float64)
+		for k, v := range teamMemberLimits(member) {
+			if old[k] != v {
+				updateData[k] = v
+				changed = true
+			}
+		}
+		// A changed default budget applies to members without their own
+		if _, ok := updateData["max_budget_in_team"]; !ok && member["max_budget_in_team"].(float64) == 0 && d.HasChange("max_budget_in_team") {
+			if v, ok := d.GetOk("max_budget_in_team"); ok {
+				updateData["max_budget_in_team"] = v.(float64)
+				changed = true
+			}
+		}
+		if !changed {
+			continue
 		}
 
-		log.Printf("[DEBUG] Adding new team members request payload: %+v", memberData)
-
-		resp, err := MakeRequest(client, "POST", "/team/member_add", memberData)
-		if err != nil {
-			return fmt.Errorf("error adding team members: %v", err)
+		if err := updateTeamMember(client, updateData); err != nil {
+			return err
 		}
-		defer resp.Body.Close()
+	}
 
-		if err := handleResponse(resp, "adding team members"); err != nil {
+	if len(membersToAdd) > 0 {
+		if err := addTeamMembers(client, d, teamID, membersToAdd); err != nil {
 			return err
 		}
 	}
@@ -190,16 +165,7 @@ func resourceLiteLLMTeamMemberAddDelete(d *schema.ResourceData, m interface{}) e
 
 	// Delete each member
 	for _, member := range members.List() {
-		m := member.(map[string]interface{})
-		deleteData := map[string]interface{}{
-			"team_id": teamID,
-		}
-		if userID, ok := m["user_id"].(string); ok && userID != "" {
-			deleteData["user_id"] = userID
-		}
-		if userEmail, ok := m["user_email"].(string); ok && userEmail != "" {
-			deleteData["user_email"] = userEmail
-		}
+		deleteData := teamMemberRef(teamID, member.(map[string]interface{}))
 
 		resp, err := MakeRequest(client, "POST", "/team/member_delete", deleteData)
 		if err != nil {
@@ -215,3 +181,98 @@ func resourceLiteLLMTeamMemberAddDelete(d *schema.ResourceData, m interface{}) e
 	d.SetId("")
 	return nil
 }
+
+// addTeamMembers adds members to the team, then sets the limits of those that
+// have their own, since they can't be set when adding a member.
+func addTeamMembers(client *Client, d *schema.ResourceData, teamID string, members []interface{}) error {
+	membersList := make([]map[string]interface{}, 0, len(members))
+	for _, member := range members {
+		memberData := teamMemberRef(teamID, member.(map[string]interface{}))
+		delete(memberData, "team_id")
+		memberData["role"] = member.(map[string]interface{})["role"].(string)
+		membersList = append(membersList, memberData)
+	}
+
+	memberData := map[string]interface{}{
+		"member":  membersList,
+		"team_id": teamID,
+	}
+
+	if v, ok := d.GetOk("max_budget_in_team"); ok {
+		memberData["max_budget_in_team"] = v.(float64)
+	}
+
+	log.Printf("[DEBUG] Adding team members request payload: %+v", memberData)
+
+	resp, err := MakeRequest(client, "POST", "/team/member_add", memberData)
+	if err != nil {
+		return fmt.Errorf("error adding team members: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if err := handleResponse(resp, "adding team members"); err != nil {
+		return err
+	}
+
+	for _, member := range members {
+		limits := teamMemberLimits(member.(map[string]interface{}))
+		if len(limits) == 0 {
+			continue
+		}
+		updateData := teamMemberRef(teamID, member.(map[string]interface{}))
+		for k, v := range limits {
+			updateData[k] = v
+		}
+		if err := updateTeamMember(client, updateData); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// teamMembersByIdentity indexes member blocks by user ID, or by email for
+// members without one.
+func teamMembersByIdentity(members []interface{}) map[string]map[string]interface{} {
+	result := make(map[string]map[string]interface{}, len(members))
+	for _, member := range members {
+		m := member.(map[string]interface{})
+		if userID, _ := m["user_id"].(string); userID != "" {
+			result[userID] = m
+		} else {
+			result["email:"+strings.ToLower(m["user_email"].(string))] = m
+		}
+	}
+	return result
+}
+
+// teamMemberRef returns the fields that identify a member in requests to the
+// team member endpoints.
+func teamMemberRef(teamID string, member map[string]interface{}) map[string]interface{} {
+	ref := map[string]interface{}{
+		"team_id": teamID,
+	}
+	if userID, ok := member["user_id"].(string); ok && userID != "" {
+		ref["user_id"] = userID
+	}
+	if userEmail, ok := member["user_email"].(string); ok && userEmail != "" {
+		ref["user_email"] = userEmail
+	}
+	return ref
+}
+
+// teamMemberLimits returns the limits set on a member block. Zero values mean
+// unset, since set elements can't hold null. LiteLLM has no way to clear a
+// member's limit, so a removed limit is not sent at all.
+func teamMemberLimits(member map[string]interface{}) map[string]interface{} {
+	limits := make(map[string]interface{})
+	if v := member["max_budget_in_team"].(float64); v != 0 {
+		limits["max_budget_in_team"] = v
+	}
+	for _, k := range []string{"tpm_limit", "rpm_limit"} {
+		if v := member[k].(int); v != 0 {
+			limits[k] = v
+		}
+	}
+	return limits
+}
@@ -0,0 +1,143 @@
+package litellm
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+// createTestTeam creates a team on the fake proxy and returns its ID.
+func createTestTeam(t *testing.T, proxy *fakeProxy) string {
+	t.Helper()
+	return applyResource(t, ResourceLiteLLMTeam(), nil, testTeamConfig(), proxy.client()).ID
+}
+
+func testTeamMemberConfig(teamID string) map[string]interface{} {
+	return map[string]interface{}{
+		"team_id":            teamID,
+		"user_id":            "user-1",
+		"user_email":         "user-1@example.com",
+		"role":               "user",
+		"max_budget_in_team": 50.0,
+	}
+}
+
+func TestResourceTeamMemberRead(t *testing.T) {
+	proxy := newFakeProxy(t)
+	client := proxy.client()
+	r := resourceLiteLLMTeamMember()
+
+	teamID := createTestTeam(t, proxy)
+	config := testTeamMemberConfig(teamID)
+	state := applyResource(t, r, nil, config, client)
+
+	state = refreshResource(t, r, state, client)
+	assertNoDiff(t, r, state, config, client)
+
+	// A role change in the UI shows up as drift
+	proxy.teams[teamID]["members_with_roles"].([]interface{})[0].(map[string]interface{})["role"] = "admin"
+	state = refreshResource(t, r, state, client)
+	if v := state.Attributes["role"]; v != "admin" {
+		t.Errorf("expected role to be refreshed to admin, got %q", v)
+	}
+
+	// A member removed in the UI is removed from state, so that it's added again
+	proxy.teams[teamID]["members_with_roles"] = []interface{}{}
+	if state := refreshResource(t, r, state, client); state != nil && state.ID != "" {
+		t.Errorf("expected the removed member to be removed from state, got %v", state)
+	}
+}
+
+func TestResourceTeamMemberImport(t *testing.T) {
+	proxy := newFakeProxy(t)
+	client := proxy.client()
+	r := resourceLiteLLMTeamMember()
+
+	teamID := createTestTeam(t, proxy)
+	config := mergeMaps(testTeamMemberConfig(teamID), map[string]interface{}{"role": "admin"})
+	applyResource(t, r, nil, config, client)
+
+	d := r.Data(&terraform.InstanceState{ID: teamID + ":user-1"})
+	imported, err := r.Importer.StateContext(context.Background(), d, client)
+	if err != nil {
+		t.Fatalf("error importing: %s", err)
+	}
+	state := refreshResource(t, r, imported[0].State(), client)
+	if state == nil || state.ID == "" {
+		t.Fatal("expected the imported member to be found")
+	}
+	assertNoDiff(t, r, state, config, client)
+
+	if _, err := r.Importer.StateContext(context.Background(), r.Data(&terraform.InstanceState{ID: "user-1"}), client); err == nil {
+		t.Error("expected an error for an import ID without a team ID")
+	}
+}
+
+func TestResourceTeamMemberUpdatesRoleAndLimits(t *testing.T) {
+	proxy := newFakeProxy(t)
+	client := proxy.client()
+	r := resourceLiteLLMTeamMember()
+
+	teamID := createTestTeam(t, proxy)
+	config := mergeMaps(testTeamMemberConfig(teamID), map[string]interface{}{"tpm_limit": 1000})
+	state := applyResource(t, r, nil, config, client)
+	if v := proxy.lastRequest("/team/member_update")["tpm_limit"]; v != 1000.0 {
+		t.Errorf("expected tpm_limit to be set after adding the member, got %#v", v)
+	}
+
+	updated := mergeMaps(config, map[string]interface{}{"role": "admin", "rpm_limit": 60, "max_budget_in_team": 75.0})
+	state = applyResource(t, r, state, updated, client)
+
+	sent := proxy.lastRequest("/team/member_update")
+	if sent["role"] != "admin" || sent["rpm_limit"] != 60.0 || sent["max_budget_in_team"] != 75.0 {
+		t.Errorf("expected the role and limits to be sent, got %v", sent)
+	}
+
+	state = refreshResource(t, r, state, client)
+	assertNoDiff(t, r, state, updated, client)
+	if v := state.Attributes["tpm_limit"]; v != "1000" {
+		t.Errorf("expected tpm_limit to be read back, got %q", v)
+	}
+}
+
+func TestResourceTeamMemberAddUpdatesRolesInPlace(t *testing.T) {
+	proxy := newFakeProxy(t)
+	client := proxy.client()
+	r := resourceLiteLLMTeamMemberAdd()
+
+	teamID := createTestTeam(t, proxy)
+	config := map[string]interface{}{
+		"team_id": teamID,
+		"member": []interface{}{
+			map[string]interface{}{"user_id": "user-1", "role": "user", "max_budget_in_team": 20.0},
+			map[string]interface{}{"user_email": "user-2@example.com", "role": "user"},
+		},
+	}
+	state := applyResource(t, r, nil, config, client)
+	if v := proxy.lastRequest("/team/member_update")["max_budget_in_team"]; v != 20.0 {
+		t.Errorf("expected the member's own budget to be set, got %#v", v)
+	}
+
+	config["member"] = []interface{}{
+		map[string]interface{}{"user_id": "user-1", "role": "admin", "max_budget_in_team": 20.0, "rpm_limit": 30},
+		map[string]interface{}{"user_email": "user-2@example.com", "role": "admin"},
+		map[string]interface{}{"user_id": "user-3", "role": "user"},
+	}
+	applyResource(t, r, state, config, client)
+
+	if n := len(proxy.requests["/team/member_delete"]); n != 0 {
+		t.Errorf("expected role changes not to remove members, got %d deletions", n)
+	}
+	roles := map[string]interface{}{}
+	for _, m := range proxy.teams[teamID]["members_with_roles"].([]interface{}) {
+		member := m.(map[string]interface{})
+		roles[member["user_id"].(string)] = member["role"]
+	}
+	if roles["user-1"] != "admin" || roles["user-3"] != "user" || len(roles) != 3 {
+		t.Errorf("unexpected members after update: %v", roles)
+	}
+	if v := proxy.memberships[teamID+":user-1"]["litellm_budget_table"]; v.(map[string]interface{})["rpm_limit"] != 30.0 {
+		t.Errorf("expected rpm_limit to be set on the existing member, got %v", v)
+	}
+}
@@ -0,0 +1,137 @@
+package litellm
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+const (
+	endpointTeamModelAdd    = "/team/model/add"
+	endpointTeamModelDelete = "/team/model/delete"
+)
+
+func resourceLiteLLMTeamModel() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceLiteLLMTeamModelCreate,
+		Read:   resourceLiteLLMTeamModelRead,
+		Delete: resourceLiteLLMTeamModelDelete,
+
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceLiteLLMTeamModelImport,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"team_id": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			"model": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+		},
+	}
+}
+
+func resourceLiteLLMTeamModelCreate(d *schema.ResourceData, m interface{}) error {
+	client := m.(*Client)
+
+	teamID := d.Get("team_id").(string)
+	model := d.Get("model").(string)
+
+	if err := changeTeamModel(client, endpointTeamModelAdd, teamID, model); err != nil {
+		return err
+	}
+
+	d.SetId(fmt.Sprintf("%s:%s", teamID, model))
+	log.Printf("[INFO] Added model %s to team %s", model, teamID)
+
+	return resourceLiteLLMTeamModelRead(d, m)
+}
+
+func resourceLiteLLMTeamModelRead(d *schema.ResourceData, m interface{}) error {
+	client := m.(*Client)
+
+	teamID := d.Get("team_id").(string)
+	model := d.Get("model").(string)
+
+	log.Printf("[INFO] Reading model %s of team %s", model, teamID)
+
+	team, err := getTeamInfo(client, teamID)
+	if err != nil {
+		return err
+	}
+	if team == nil || !containsString(team.Models, model) {
+		log.Printf("[WARN] Model %s not found in team %s, removing from state", model, teamID)
+		d.SetId("")
+		return nil
+	}
+
+	return nil
+}
+
+func resourceLiteLLMTeamModelDelete(d *schema.ResourceData, m interface{}) error {
+	client := m.(*Client)
+
+	teamID := d.Get("team_id").(string)
+	model := d.Get("model").(string)
+
+	team, err := getTeamInfo(client, teamID)
+	if err != nil {
+		return err
+	}
+	if team != nil {
+		if err := changeTeamModel(client, endpointTeamModelDelete, teamID, model); err != nil {
+			return err
+		}
+	}
+
+	log.Printf("[INFO] Removed model %s from team %s", model, teamID)
+	d.SetId("")
+	return nil
+}
+
+// resourceLiteLLMTeamModelImport accepts "<team_id>:<model>". Model names can
+// contain colons, so only the first one separates the two.
+func resourceLiteLLMTeamModelImport(ctx context.Context, d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+	teamID, model, ok := strings.Cut(d.Id(), ":")
+	if !ok || teamID == "" || model == "" {
+		return nil, fmt.Errorf("invalid import ID %q, expected <team_id>:<model>", d.Id())
+	}
+
+	d.Set("team_id", teamID)
+	d.Set("model", model)
+	return []*schema.ResourceData{d}, nil
+}
+
+// changeTeamModel adds a model to or removes a model from a team without
+// touching the team's other models.
+func changeTeamModel(client *Client, endpoint, teamID, model string) error {
+	modelData := map[string]interface{}{
+		"team_id": teamID,
+		"models":  []string{model},
+	}
+
+	resp, err := MakeRequest(client, "POST", endpoint, modelData)
+	if err != nil {
+		return fmt.Errorf("error changing team models: %w", err)
+	}
+	defer resp.Body.Close()
+
+	return handleResponse(resp, "changing team models")
+}
+
+func containsString(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,89 @@
+package litellm
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestResourceTeamModel(t *testing.T) {
+	proxy := newFakeProxy(t)
+	client := proxy.client()
+	r := resourceLiteLLMTeamModel()
+
+	teamID := createTestTeam(t, proxy)
+	config := map[string]interface{}{"team_id": teamID, "model": "claude-3"}
+	state := applyResource(t, r, nil, config, client)
+	if state.ID != teamID+":claude-3" {
+		t.Errorf("expected ID %s:claude-3, got %s", teamID, state.ID)
+	}
+	if got := proxy.teams[teamID]["models"]; !reflect.DeepEqual(got, []interface{}{"gpt-4", "claude-3"}) {
+		t.Errorf("expected the model to be added next to the team's other models, got %v", got)
+	}
+
+	state = refreshResource(t, r, state, client)
+	assertNoDiff(t, r, state, config, client)
+
+	imported, err := r.Importer.StateContext(context.Background(), r.Data(&terraform.InstanceState{ID: teamID + ":claude-3"}), client)
+	if err != nil {
+		t.Fatalf("error importing: %s", err)
+	}
+	if imported := refreshResource(t, r, imported[0].State(), client); imported == nil || imported.ID == "" {
+		t.Error("expected the imported model to be found")
+	} else {
+		assertNoDiff(t, r, imported, config, client)
+	}
+	if _, err := r.Importer.StateContext(context.Background(), r.Data(&terraform.InstanceState{ID: "claude-3"}), client); err == nil {
+		t.Error("expected an error for an import ID without a team ID")
+	}
+
+	if err := destroyResource(t, r, state, client); err != nil {
+		t.Fatalf("error destroying: %s", err)
+	}
+	if got := proxy.teams[teamID]["models"]; !reflect.DeepEqual(got, []interface{}{"gpt-4"}) {
+		t.Errorf("expected only the granted model to be removed, got %v", got)
+	}
+
+	// A model removed outside of Terraform is removed from state
+	state = applyResource(t, r, nil, config, client)
+	proxy.teams[teamID]["models"] = []interface{}{"gpt-4"}
+	if state := refreshResource(t, r, state, client); state != nil && state.ID != "" {
+		t.Errorf("expected the removed model to be removed from state, got %v", state)
+	}
+}
+
+func TestResourceTeamUpdateKeepsModelGrants(t *testing.T) {
+	proxy := newFakeProxy(t)
+	client := proxy.client()
+	team := ResourceLiteLLMTeam()
+
+	teamConfig := map[string]interface{}{"team_alias": "engineering", "max_budget": 100.0}
+	teamState := applyResource(t, team, nil, teamConfig, client)
+	applyResource(t, resourceLiteLLMTeamModel(), nil, map[string]interface{}{"team_id": teamState.ID, "model": "claude-3"}, client)
+
+	teamState = refreshResource(t, team, teamState, client)
+	assertNoDiff(t, team, teamState, teamConfig, client)
+
+	teamConfig["max_budget"] = 150.0
+	teamState = applyResource(t, team, teamState, teamConfig, client)
+	if _, ok := proxy.lastRequest("/team/update")["models"]; ok {
+		t.Error("expected unconfigured models not to be sent")
+	}
+	if got := proxy.teams[teamState.ID]["models"]; !reflect.DeepEqual(got, []interface{}{"claude-3"}) {
+		t.Errorf("expected the granted model to be kept, got %v", got)
+	}
+
+	// Configured models are only sent when they change
+	config := mergeMaps(testTeamConfig(), map[string]interface{}{"team_alias": "platform"})
+	teamState = applyResource(t, team, nil, config, client)
+	teamState = applyResource(t, team, teamState, mergeMaps(config, map[string]interface{}{"max_budget": 150.0}), client)
+	if _, ok := proxy.lastRequest("/team/update")["models"]; ok {
+		t.Error("expected unchanged models not to be sent")
+	}
+	applyResource(t, team, teamState, mergeMaps(config, map[string]interface{}{"models": []interface{}{"gpt-4o"}}), client)
+	if got := proxy.lastRequest("/team/update")["models"]; !reflect.DeepEqual(got, []interface{}{"gpt-4o"}) {
+		t.Errorf("expected changed models to be sent, got %v", got)
+	}
+}
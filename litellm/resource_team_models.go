@@ -0,0 +1,117 @@
+package litellm
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// noDefaultModels is LiteLLM's model name for "no models". An empty models
+// list grants a team access to every model on the proxy, so it is used when
+// the authoritative model list is removed.
+const noDefaultModels = "no-default-models"
+
+func resourceLiteLLMTeamModels() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceLiteLLMTeamModelsCreate,
+		Read:   resourceLiteLLMTeamModelsRead,
+		Update: resourceLiteLLMTeamModelsUpdate,
+		Delete: resourceLiteLLMTeamModelsDelete,
+
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"team_id": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			// An empty list would grant every model, so it isn't accepted
+			"models": {
+				Type:     schema.TypeSet,
+				Required: true,
+				MinItems: 1,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+		},
+	}
+}
+
+func resourceLiteLLMTeamModelsCreate(d *schema.ResourceData, m interface{}) error {
+	teamID := d.Get("team_id").(string)
+	if err := setTeamModels(m.(*Client), teamID, expandStringList(d.Get("models").(*schema.Set).List())); err != nil {
+		return err
+	}
+
+	d.SetId(teamID)
+	log.Printf("[INFO] Set models for team %s", teamID)
+
+	return resourceLiteLLMTeamModelsRead(d, m)
+}
+
+func resourceLiteLLMTeamModelsRead(d *schema.ResourceData, m interface{}) error {
+	client := m.(*Client)
+
+	log.Printf("[INFO] Reading models for team %s", d.Id())
+
+	team, err := getTeamInfo(client, d.Id())
+	if err != nil {
+		return err
+	}
+	if team == nil {
+		log.Printf("[WARN] Team with ID %s not found, removing team models from state", d.Id())
+		d.SetId("")
+		return nil
+	}
+
+	d.Set("team_id", d.Id())
+	d.Set("models", team.Models)
+
+	return nil
+}
+
+func resourceLiteLLMTeamModelsUpdate(d *schema.ResourceData, m interface{}) error {
+	if err := setTeamModels(m.(*Client), d.Id(), expandStringList(d.Get("models").(*schema.Set).List())); err != nil {
+		return err
+	}
+
+	log.Printf("[INFO] Updated models for team %s", d.Id())
+	return resourceLiteLLMTeamModelsRead(d, m)
+}
+
+func resourceLiteLLMTeamModelsDelete(d *schema.ResourceData, m interface{}) error {
+	client := m.(*Client)
+
+	team, err := getTeamInfo(client, d.Id())
+	if err != nil {
+		return err
+	}
+	if team != nil {
+		if err := setTeamModels(client, d.Id(), []string{noDefaultModels}); err != nil {
+			return err
+		}
+	}
+
+	log.Printf("[INFO] Removed models from team %s", d.Id())
+	d.SetId("")
+	return nil
+}
+
+// setTeamModels replaces a team's model list.
+func setTeamModels(client *Client, teamID string, models []string) error {
+	teamData := map[string]interface{}{
+		"team_id": teamID,
+		"models":  models,
+	}
+
+	resp, err := MakeRequest(client, "POST", endpointTeamUpdate, teamData)
+	if err != nil {
+		return fmt.Errorf("error updating team models: %w", err)
+	}
+	defer resp.Body.Close()
+
+	return handleResponse(resp, "updating team models")
+}
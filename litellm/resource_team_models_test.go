@@ -0,0 +1,70 @@
+package litellm
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestResourceTeamModels(t *testing.T) {
+	proxy := newFakeProxy(t)
+	client := proxy.client()
+	r := resourceLiteLLMTeamModels()
+
+	teamID := createTestTeam(t, proxy)
+	config := map[string]interface{}{"team_id": teamID, "models": []interface{}{"claude-3", "gpt-4o"}}
+	state := applyResource(t, r, nil, config, client)
+	if got := proxy.teams[teamID]["models"]; !reflect.DeepEqual(got, []interface{}{"claude-3", "gpt-4o"}) {
+		t.Errorf("expected the team's models to be replaced, got %v", got)
+	}
+
+	state = refreshResource(t, r, state, client)
+	assertNoDiff(t, r, state, config, client)
+
+	// Models added outside of Terraform show up as drift
+	proxy.teams[teamID]["models"] = []interface{}{"claude-3", "gpt-4o", "gpt-4"}
+	state = refreshResource(t, r, state, client)
+	if diff := planResource(t, r, state, config, client); diff == nil || diff.Empty() {
+		t.Error("expected the added model to be planned for removal")
+	}
+
+	updated := map[string]interface{}{"team_id": teamID, "models": []interface{}{"gpt-4o"}}
+	state = applyResource(t, r, state, updated, client)
+	if got := proxy.teams[teamID]["models"]; !reflect.DeepEqual(got, []interface{}{"gpt-4o"}) {
+		t.Errorf("expected the team's models to be updated, got %v", got)
+	}
+
+	imported, err := r.Importer.StateContext(context.Background(), r.Data(&terraform.InstanceState{ID: teamID}), client)
+	if err != nil {
+		t.Fatalf("error importing: %s", err)
+	}
+	assertNoDiff(t, r, refreshResource(t, r, imported[0].State(), client), updated, client)
+
+	if err := destroyResource(t, r, state, client); err != nil {
+		t.Fatalf("error destroying: %s", err)
+	}
+	if got := proxy.teams[teamID]["models"]; !reflect.DeepEqual(got, []interface{}{noDefaultModels}) {
+		t.Errorf("expected the team to be left without models, got %v", got)
+	}
+
+	// The models of a deleted team are removed from state
+	delete(proxy.teams, teamID)
+	if state := refreshResource(t, r, state, client); state != nil && state.ID != "" {
+		t.Errorf("expected the models of a deleted team to be removed from state, got %v", state)
+	}
+}
+
+func TestResourceTeamModelsRejectsEmptyList(t *testing.T) {
+	r := resourceLiteLLMTeamModels()
+
+	diags := r.Validate(terraform.NewResourceConfigRaw(map[string]interface{}{"team_id": "team-1", "models": []interface{}{}}))
+	if !diags.HasError() {
+		t.Error("expected an empty models list to be rejected")
+	}
+	diags = r.Validate(terraform.NewResourceConfigRaw(map[string]interface{}{"team_id": "team-1", "models": []interface{}{noDefaultModels}}))
+	if diags.HasError() {
+		t.Errorf("expected %s to be accepted, got %v", noDefaultModels, diags)
+	}
+}
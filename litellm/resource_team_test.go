@@ -0,0 +1,611 @@
+package litellm
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func testTeamConfig() map[string]interface{} {
+	return map[string]interface{}{
+		"team_alias": "engineering",
+		"models":     []interface{}{"gpt-4"},
+		"max_budget": 100.0,
+	}
+}
+
+// addTeamKey stores a key owned by teamID directly on the fake proxy.
+func addTeamKey(proxy *fakeProxy, teamID, alias string) string {
+	proxy.mu.Lock()
+	defer proxy.mu.Unlock()
+
+	token := hashToken(proxy.newSecret())
+	proxy.keys[token] = map[string]interface{}{
+		"token":     token,
+		"team_id":   teamID,
+		"key_alias": alias,
+		"metadata":  map[string]interface{}{},
+	}
+	return token
+}
+
+func TestResourceTeamOnDestroyFail(t *testing.T) {
+	proxy := newFakeProxy(t)
+	client := proxy.client()
+	r := ResourceLiteLLMTeam()
+
+	state := applyResource(t, r, nil, mergeMaps(testTeamConfig(), map[string]interface{}{
+		"on_destroy": teamOnDestroyFail,
+	}), client)
+	addTeamKey(proxy, state.ID, "ci")
+	addTeamKey(proxy, state.ID, "batch")
+
+	err := destroyResource(t, r, state, client)
+	if err == nil {
+		t.Fatal("expected destroying a team that owns keys to fail")
+	}
+	for _, alias := range []string{"ci", "batch"} {
+		if !strings.Contains(err.Error(), alias) {
+			t.Errorf("expected the error to list key %q, got: %s", alias, err)
+		}
+	}
+	if _, ok := proxy.teams[state.ID]; !ok {
+		t.Error("expected the team to be kept")
+	}
+	if len(proxy.keys) != 2 {
+		t.Errorf("expected the keys to be kept, got %d keys", len(proxy.keys))
+	}
+}
+
+func TestResourceTeamOnDestroyUnset(t *testing.T) {
+	proxy := newFakeProxy(t)
+	client := proxy.client()
+	r := ResourceLiteLLMTeam()
+
+	// Without on_destroy, the team is deleted without looking at its keys,
+	// and the proxy decides what happens to them
+	state := applyResource(t, r, nil, testTeamConfig(), client)
+	if v := state.Attributes["on_destroy"]; v != "" {
+		t.Fatalf("expected on_destroy to have no default, got %q", v)
+	}
+	token := addTeamKey(proxy, state.ID, "ci")
+
+	err := destroyResource(t, r, state, client)
+	if err == nil || !strings.Contains(err.Error(), "Team still has keys") {
+		t.Errorf("expected the proxy's error, got %v", err)
+	}
+	if n := len(proxy.requests["/team/delete"]); n != 1 {
+		t.Errorf("expected the team deletion to be sent, got %d calls", n)
+	}
+	if n := len(proxy.requests["/key/list"]); n != 0 {
+		t.Errorf("expected the team's keys not to be listed, got %d calls", n)
+	}
+
+	delete(proxy.keys, token)
+	if err := destroyResource(t, r, state, client); err != nil {
+		t.Fatalf("error destroying: %s", err)
+	}
+	if _, ok := proxy.teams[state.ID]; ok {
+		t.Error("expected the team to be deleted")
+	}
+}
+
+func TestResourceTeamOnDestroyDeleteKeys(t *testing.T) {
+	proxy := newFakeProxy(t)
+	client := proxy.client()
+	r := ResourceLiteLLMTeam()
+
+	state := applyResource(t, r, nil, mergeMaps(testTeamConfig(), map[string]interface{}{
+		"on_destroy": teamOnDestroyDeleteKeys,
+	}), client)
+	addTeamKey(proxy, state.ID, "ci")
+	other := addTeamKey(proxy, "other-team", "other")
+
+	if err := destroyResource(t, r, state, client); err != nil {
+		t.Fatalf("error destroying team: %s", err)
+	}
+	if _, ok := proxy.teams[state.ID]; ok {
+		t.Error("expected the team to be deleted")
+	}
+	if len(proxy.keys) != 1 || proxy.keys[other] == nil {
+		t.Errorf("expected only the team's keys to be deleted, got %v", proxy.keys)
+	}
+}
+
+func TestResourceTeamOnDestroyReassignKeys(t *testing.T) {
+	proxy := newFakeProxy(t)
+	client := proxy.client()
+	r := ResourceLiteLLMTeam()
+
+	target := applyResource(t, r, nil, mergeMaps(testTeamConfig(), map[string]interface{}{
+		"team_alias": "platform",
+	}), client)
+
+	// on_destroy is changed in place, without updating the team
+	state := applyResource(t, r, nil, testTeamConfig(), client)
+	updates := len(proxy.requests["/team/update"])
+	state = applyResource(t, r, state, mergeMaps(testTeamConfig(), map[string]interface{}{
+		"on_destroy":               teamOnDestroyReassignKeys,
+		"reassign_keys_to_team_id": target.ID,
+	}), client)
+	if len(proxy.requests["/team/update"]) != updates {
+		t.Error("expected changing on_destroy not to update the team")
+	}
+
+	token := addTeamKey(proxy, state.ID, "ci")
+
+	if err := destroyResource(t, r, state, client); err != nil {
+		t.Fatalf("error destroying team: %s", err)
+	}
+	if _, ok := proxy.teams[state.ID]; ok {
+		t.Error("expected the team to be deleted")
+	}
+	key := proxy.keys[token]
+	if key == nil {
+		t.Fatal("expected the key to be kept")
+	}
+	if key["team_id"] != target.ID || key["key_alias"] != "ci" {
+		t.Errorf("expected the key to move to team %s with its settings, got %v", target.ID, key)
+	}
+}
+
+func TestResourceTeamOnDestroyValidation(t *testing.T) {
+	r := ResourceLiteLLMTeam()
+
+	configs := map[string]map[string]interface{}{
+		"missing target": {"on_destroy": teamOnDestroyReassignKeys},
+		"unused target":  {"on_destroy": teamOnDestroyDeleteKeys, "reassign_keys_to_team_id": "team-2"},
+	}
+	for name, config := range configs {
+		if _, err := r.Diff(context.Background(), nil, terraform.NewResourceConfigRaw(mergeMaps(testTeamConfig(), config)), nil); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
+
+// sentPermissions returns the permissions of the last permissions update, sorted.
+func sentPermissions(proxy *fakeProxy) []string {
+	permissions := expandInterfaceStringList(proxy.lastRequest("/team/permissions_update")["team_member_permissions"])
+	sort.Strings(permissions)
+	return permissions
+}
+
+func TestResourceTeamMemberPermissions(t *testing.T) {
+	proxy := newFakeProxy(t)
+	client := proxy.client()
+	r := ResourceLiteLLMTeam()
+
+	invalid := mergeMaps(testTeamConfig(), map[string]interface{}{
+		"team_member_permissions": []interface{}{"/key/info", "/team/delete"},
+	})
+	if diags := r.Validate(terraform.NewResourceConfigRaw(invalid)); !diags.HasError() {
+		t.Error("expected /team/delete to be rejected as a team member permission")
+	}
+
+	config := mergeMaps(testTeamConfig(), map[string]interface{}{
+		"team_member_permissions": []interface{}{"/key/info", "/key/generate"},
+	})
+	if diags := r.Validate(terraform.NewResourceConfigRaw(config)); diags.HasError() {
+		t.Fatalf("expected the permissions to be valid, got %v", diags)
+	}
+	state := applyResource(t, r, nil, config, client)
+	if n := len(proxy.requests["/team/permissions_update"]); n != 1 {
+		t.Errorf("expected the permissions to be set once, got %d calls", n)
+	}
+	if sent := sentPermissions(proxy); !reflect.DeepEqual(sent, []string{"/key/generate", "/key/info"}) {
+		t.Errorf("expected the permissions to be set, got %v", sent)
+	}
+	state = refreshResource(t, r, state, client)
+	assertNoDiff(t, r, state, config, client)
+
+	updated := mergeMaps(config, map[string]interface{}{
+		"team_member_permissions": []interface{}{"/key/info", "/key/update"},
+	})
+	state = applyResource(t, r, state, updated, client)
+	if n := len(proxy.requests["/team/permissions_update"]); n != 2 {
+		t.Fatalf("expected the update to set the permissions, got %d calls", n-1)
+	}
+	if sent := sentPermissions(proxy); !reflect.DeepEqual(sent, []string{"/key/info", "/key/update"}) {
+		t.Errorf("expected the new permissions to be sent, got %v", sent)
+	}
+	state = refreshResource(t, r, state, client)
+	assertNoDiff(t, r, state, updated, client)
+
+	// Removing them keeps the team's current permissions
+	assertNoDiff(t, r, state, testTeamConfig(), client)
+}
+
+func TestResourceTeamCallerSuppliedID(t *testing.T) {
+	proxy := newFakeProxy(t)
+	client := proxy.client()
+	r := ResourceLiteLLMTeam()
+
+	config := mergeMaps(testTeamConfig(), map[string]interface{}{
+		"team_id":         "project-1234",
+		"organization_id": "org-1",
+	})
+	state := applyResource(t, r, nil, config, client)
+	if state.ID != "project-1234" {
+		t.Fatalf("expected the team to use the configured ID, got %q", state.ID)
+	}
+	if _, ok := proxy.teams["project-1234"]; !ok {
+		t.Error("expected the team to be created with the configured ID")
+	}
+	assertNoDiff(t, r, state, config, client)
+
+	diff := planResource(t, r, state, mergeMaps(config, map[string]interface{}{"team_id": "project-5678"}), client)
+	if !diff.RequiresNew() {
+		t.Error("expected changing team_id to replace the team")
+	}
+}
+
+func TestResourceTeamOrganization(t *testing.T) {
+	proxy := newFakeProxy(t)
+	client := proxy.client()
+	r := ResourceLiteLLMTeam()
+
+	config := mergeMaps(testTeamConfig(), map[string]interface{}{"organization_id": "org-1"})
+	state := applyResource(t, r, nil, config, client)
+
+	// A configured change can't be sent to the proxy, so it replaces the team
+	diff := planResource(t, r, state, mergeMaps(config, map[string]interface{}{"organization_id": "org-2"}), client)
+	if diff == nil || !diff.RequiresNew() || !diff.Attributes["organization_id"].RequiresNew {
+		t.Errorf("expected changing organization_id to replace the team, got %v", diff)
+	}
+	if desc := r.Schema["organization_id"].Description; !strings.Contains(desc, "replaces the team") {
+		t.Errorf("expected the organization_id description to explain the replacement, got %q", desc)
+	}
+
+	// Removing it from the configuration keeps the team where it is
+	unset := testTeamConfig()
+	assertNoDiff(t, r, state, unset, client)
+
+	// A team moved outside of Terraform is only replaced if the configuration
+	// says otherwise
+	proxy.teams[state.ID]["organization_id"] = "org-3"
+	state = refreshResource(t, r, state, client)
+	if v := state.Attributes["organization_id"]; v != "org-3" {
+		t.Fatalf("expected refresh to read the new organization, got %q", v)
+	}
+	assertNoDiff(t, r, state, unset, client)
+	if diff := planResource(t, r, state, config, client); diff == nil || !diff.RequiresNew() {
+		t.Errorf("expected a configured organization to replace the moved team, got %v", diff)
+	}
+}
+
+func TestResourceTeamReadAndImport(t *testing.T) {
+	proxy := newFakeProxy(t)
+	client := proxy.client()
+	r := ResourceLiteLLMTeam()
+
+	proxy.teams["team-1"] = map[string]interface{}{
+		"team_id":         "team-1",
+		"team_alias":      "research",
+		"models":          []interface{}{"gpt-4", "claude-3"},
+		"tpm_limit":       1000,
+		"rpm_limit":       60,
+		"max_budget":      250.0,
+		"budget_duration": "30d",
+		"blocked":         false,
+		"metadata": map[string]interface{}{
+			"cost_center":       "r-and-d",
+			"model_rpm_limit":   map[string]interface{}{"gpt-4": 30},
+			"callback_settings": map[string]interface{}{"success_callback": []interface{}{"langfuse"}},
+		},
+		"team_member_permissions": defaultTeamMemberPermissions,
+		"members_with_roles":      []interface{}{},
+	}
+
+	imported, err := r.Importer.StateContext(context.Background(), r.Data(&terraform.InstanceState{ID: "team-1"}), client)
+	if err != nil {
+		t.Fatalf("error importing: %s", err)
+	}
+	state := refreshResource(t, r, imported[0].State(), client)
+	if state == nil || state.ID != "team-1" {
+		t.Fatalf("expected the team to be imported, got %v", state)
+	}
+
+	want := map[string]string{
+		"team_alias":            "research",
+		"models.#":              "2",
+		"models.1":              "claude-3",
+		"tpm_limit":             "1000",
+		"rpm_limit":             "60",
+		"max_budget":            "250",
+		"budget_duration":       "30d",
+		"metadata.%":            "1",
+		"metadata.cost_center":  "r-and-d",
+		"model_rpm_limit.gpt-4": "30",
+	}
+	for attr, v := range want {
+		if got := state.Attributes[attr]; got != v {
+			t.Errorf("%s = %q, want %q", attr, got, v)
+		}
+	}
+
+	assertNoDiff(t, r, state, map[string]interface{}{
+		"team_alias":      "research",
+		"models":          []interface{}{"gpt-4", "claude-3"},
+		"tpm_limit":       1000,
+		"rpm_limit":       60,
+		"max_budget":      250.0,
+		"budget_duration": "30d",
+		"metadata":        map[string]interface{}{"cost_center": "r-and-d"},
+		"model_rpm_limit": map[string]interface{}{"gpt-4": 30},
+	}, client)
+}
+
+func TestResourceTeamRemovedWhenNotFound(t *testing.T) {
+	responses := map[string]struct {
+		status int
+		body   string
+	}{
+		"404":             {http.StatusNotFound, `{"detail":{"error":"Team not found"}}`},
+		"team not found":  {http.StatusBadRequest, `{"detail":{"error":"Team not found, passed team_id=team-1"}}`},
+		"team not exists": {http.StatusInternalServerError, `{"detail":{"error":"Team doesn't exist in db"}}`},
+		"empty team_info": {http.StatusOK, `{"team_id":"team-1","team_info":{}}`},
+	}
+	for name, response := range responses {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(response.status)
+			w.Write([]byte(response.body))
+		}))
+		defer server.Close()
+
+		r := ResourceLiteLLMTeam()
+		state := refreshResource(t, r, &terraform.InstanceState{ID: "team-1", Attributes: map[string]string{"team_alias": "engineering"}}, NewClient(server.URL, "sk-master"))
+		if state != nil && state.ID != "" {
+			t.Errorf("%s: expected the team to be removed from state, got %v", name, state)
+		}
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, `{"detail":{"error":"Internal error"}}`, http.StatusInternalServerError)
+	}))
+	defer server.Close()
+	r := ResourceLiteLLMTeam()
+	if _, diags := r.RefreshWithoutUpgrade(context.Background(), &terraform.InstanceState{ID: "team-1"}, NewClient(server.URL, "sk-master")); !diags.HasError() {
+		t.Error("expected other errors to fail the refresh")
+	}
+}
+
+func TestResourceTeamClearsRemovedLimits(t *testing.T) {
+	proxy := newFakeProxy(t)
+	client := proxy.client()
+	r := ResourceLiteLLMTeam()
+
+	config := mergeMaps(testTeamConfig(), map[string]interface{}{
+		"tpm_limit":       1000,
+		"rpm_limit":       0,
+		"budget_duration": "30d",
+	})
+	state := applyResource(t, r, nil, config, client)
+	if v, ok := proxy.lastRequest("/team/new")["rpm_limit"]; !ok || v != 0.0 {
+		t.Errorf("expected a limit of 0 to be sent, got %#v", v)
+	}
+	state = refreshResource(t, r, state, client)
+	assertNoDiff(t, r, state, config, client)
+
+	cleared := map[string]interface{}{"team_alias": "engineering", "models": []interface{}{"gpt-4"}}
+	state = applyResource(t, r, state, cleared, client)
+	sent := proxy.lastRequest("/team/update")
+	for _, attr := range []string{"tpm_limit", "rpm_limit", "max_budget", "budget_duration"} {
+		if v, ok := sent[attr]; !ok || v != nil {
+			t.Errorf("expected %s to be cleared, got %#v", attr, v)
+		}
+	}
+	state = refreshResource(t, r, state, client)
+	assertNoDiff(t, r, state, cleared, client)
+}
+
+func TestResourceTeamClearsRemovedMetadata(t *testing.T) {
+	for _, attr := range []string{"metadata", "metadata_json"} {
+		t.Run(attr, func(t *testing.T) {
+			proxy := newFakeProxy(t)
+			client := proxy.client()
+			r := ResourceLiteLLMTeam()
+
+			base := testTeamConfig()
+			config := mergeMaps(base, map[string]interface{}{attr: map[string]interface{}{"cost_center": "r-and-d"}})
+			if attr == "metadata_json" {
+				config[attr] = `{"cost_center": "r-and-d"}`
+			}
+			state := applyResource(t, r, nil, config, client)
+			applyResource(t, resourceLiteLLMTeamCallback(), nil, testTeamCallbackConfig(state.ID), client)
+			settings := mergeMaps(callbackSettings(proxy, state.ID))
+			state = refreshResource(t, r, state, client)
+			assertNoDiff(t, r, state, config, client)
+
+			state = applyResource(t, r, state, base, client)
+			metadata := proxy.teams[state.ID]["metadata"].(map[string]interface{})
+			if _, ok := metadata["cost_center"]; ok {
+				t.Errorf("expected the removed metadata to be cleared, got %v", metadata)
+			}
+			if !reflect.DeepEqual(metadata["callback_settings"], settings) {
+				t.Errorf("expected the callback settings to be kept, got %v", metadata)
+			}
+			state = refreshResource(t, r, state, client)
+			assertNoDiff(t, r, state, base, client)
+		})
+	}
+}
+
+func TestResourceTeamMemberDefaults(t *testing.T) {
+	proxy := newFakeProxy(t)
+	client := proxy.client()
+	r := ResourceLiteLLMTeam()
+
+	config := mergeMaps(testTeamConfig(), map[string]interface{}{
+		"metadata":                 map[string]interface{}{"cost_center": "r-and-d"},
+		"team_member_budget":       25.0,
+		"team_member_rpm_limit":    10,
+		"team_member_tpm_limit":    1000,
+		"team_member_key_duration": "30d",
+	})
+	state := applyResource(t, r, nil, config, client)
+	state = refreshResource(t, r, state, client)
+	assertNoDiff(t, r, state, config, client)
+	if v := state.Attributes["metadata.%"]; v != "1" {
+		t.Errorf("expected team_member_key_duration to be kept out of metadata, got %s entries", v)
+	}
+
+	updated := mergeMaps(config, map[string]interface{}{
+		"team_member_budget":       50.0,
+		"team_member_key_duration": "7d",
+	})
+	state = applyResource(t, r, state, updated, client)
+	sent := proxy.lastRequest("/team/update")
+	if sent["team_member_budget"] != 50.0 || sent["team_member_key_duration"] != "7d" {
+		t.Errorf("expected the changed defaults to be sent, got %v", sent)
+	}
+	state = refreshResource(t, r, state, client)
+	assertNoDiff(t, r, state, updated, client)
+
+	// The proxy can't remove the defaults, so removing them keeps them
+	removed := mergeMaps(testTeamConfig(), map[string]interface{}{
+		"metadata": map[string]interface{}{"cost_center": "r-and-d"},
+	})
+	assertNoDiff(t, r, state, removed, client)
+	state = applyResource(t, r, state, mergeMaps(removed, map[string]interface{}{"max_budget": 150.0}), client)
+	state = refreshResource(t, r, state, client)
+	if v := state.Attributes["team_member_key_duration"]; v != "7d" {
+		t.Errorf("expected team_member_key_duration to be kept on updates, got %q", v)
+	}
+	if v := state.Attributes["team_member_budget"]; v != "50" {
+		t.Errorf("expected team_member_budget to be kept on updates, got %q", v)
+	}
+}
+
+func TestResourceTeamBlocked(t *testing.T) {
+	proxy := newFakeProxy(t)
+	client := proxy.client()
+	r := ResourceLiteLLMTeam()
+
+	config := testTeamConfig()
+	state := applyResource(t, r, nil, config, client)
+
+	// Blocked in the UI, the team is unblocked again through its own endpoint
+	proxy.teams[state.ID]["blocked"] = true
+	state = refreshResource(t, r, state, client)
+	if state.Attributes["blocked"] != "true" {
+		t.Fatalf("expected refresh to show the team as blocked, got %q", state.Attributes["blocked"])
+	}
+	updates := len(proxy.requests["/team/update"])
+	state = applyResource(t, r, state, config, client)
+	if n := len(proxy.requests["/team/unblock"]); n != 1 {
+		t.Errorf("expected the team to be unblocked once, got %d calls", n)
+	}
+	if n := len(proxy.requests["/team/update"]); n != updates {
+		t.Errorf("expected unblocking not to update the team, got %d calls", n-updates)
+	}
+	if proxy.teams[state.ID]["blocked"] != false {
+		t.Error("expected the team to be unblocked")
+	}
+	assertNoDiff(t, r, state, config, client)
+}
+
+func TestResourceTeamBlockedReasonKeepsCallbacks(t *testing.T) {
+	proxy := newFakeProxy(t)
+	client := proxy.client()
+	r := ResourceLiteLLMTeam()
+
+	config := testTeamConfig()
+	state := applyResource(t, r, nil, config, client)
+	applyResource(t, resourceLiteLLMTeamCallback(), nil, testTeamCallbackConfig(state.ID), client)
+	settings := mergeMaps(callbackSettings(proxy, state.ID))
+
+	blocked := mergeMaps(config, map[string]interface{}{
+		"blocked":        true,
+		"blocked_reason": "budget review",
+	})
+	state = applyResource(t, r, state, blocked, client)
+	metadata := proxy.teams[state.ID]["metadata"].(map[string]interface{})
+	if metadata["blocked_reason"] != "budget review" {
+		t.Errorf("expected the reason to be stored in metadata, got %v", metadata)
+	}
+	if !reflect.DeepEqual(metadata["callback_settings"], settings) {
+		t.Errorf("expected setting the reason to keep the callback settings, got %v", metadata["callback_settings"])
+	}
+	state = refreshResource(t, r, state, client)
+	if state.Attributes["blocked_reason"] != "budget review" {
+		t.Errorf("expected the reason to be read back, got %q", state.Attributes["blocked_reason"])
+	}
+	if _, ok := state.Attributes["metadata.blocked_reason"]; ok {
+		t.Error("expected the reason not to show up in metadata")
+	}
+	assertNoDiff(t, r, state, blocked, client)
+
+	state = applyResource(t, r, state, config, client)
+	metadata = proxy.teams[state.ID]["metadata"].(map[string]interface{})
+	if _, ok := metadata["blocked_reason"]; ok {
+		t.Errorf("expected clearing the reason to remove it from metadata, got %v", metadata)
+	}
+	if !reflect.DeepEqual(metadata["callback_settings"], settings) {
+		t.Errorf("expected clearing the reason to keep the callback settings, got %v", metadata["callback_settings"])
+	}
+	state = refreshResource(t, r, state, client)
+	assertNoDiff(t, r, state, config, client)
+}
+
+func TestResourceTeamModelSettings(t *testing.T) {
+	proxy := newFakeProxy(t)
+	client := proxy.client()
+	r := ResourceLiteLLMTeam()
+
+	config := mergeMaps(testTeamConfig(), map[string]interface{}{
+		"model_aliases":   map[string]interface{}{"fast": "gpt-4o-mini"},
+		"model_rpm_limit": map[string]interface{}{"gpt-4": 30},
+		"model_tpm_limit": map[string]interface{}{"gpt-4": 5000},
+		"model_budget": []interface{}{
+			map[string]interface{}{"model": "gpt-4", "budget_limit": 25.0, "time_period": "1d"},
+		},
+	})
+	state := applyResource(t, r, nil, config, client)
+	sent := proxy.lastRequest("/team/new")
+	if !reflect.DeepEqual(sent["model_max_budget"], map[string]interface{}{
+		"gpt-4": map[string]interface{}{"budget_limit": 25.0, "time_period": "1d"},
+	}) {
+		t.Errorf("expected the per-model budget to be sent, got %v", sent["model_max_budget"])
+	}
+	state = refreshResource(t, r, state, client)
+	assertNoDiff(t, r, state, config, client)
+
+	updated := mergeMaps(testTeamConfig(), map[string]interface{}{
+		"model_aliases":   map[string]interface{}{"fast": "gpt-4o-mini", "smart": "gpt-4"},
+		"model_rpm_limit": map[string]interface{}{"gpt-4": 60, "gpt-4o": 120},
+		"model_tpm_limit": map[string]interface{}{"gpt-4o": 8000},
+		"model_budget": []interface{}{
+			map[string]interface{}{"model": "gpt-4", "budget_limit": 50.0, "time_period": "7d"},
+		},
+	})
+	state = applyResource(t, r, state, updated, client)
+	sent = proxy.lastRequest("/team/update")
+	for attr, want := range map[string]interface{}{
+		"model_aliases":   map[string]interface{}{"fast": "gpt-4o-mini", "smart": "gpt-4"},
+		"model_rpm_limit": map[string]interface{}{"gpt-4": 60.0, "gpt-4o": 120.0},
+		"model_tpm_limit": map[string]interface{}{"gpt-4o": 8000.0},
+	} {
+		if !reflect.DeepEqual(sent[attr], want) {
+			t.Errorf("expected %s to be updated to %v, got %v", attr, want, sent[attr])
+		}
+	}
+	state = refreshResource(t, r, state, client)
+	assertNoDiff(t, r, state, updated, client)
+
+	cleared := testTeamConfig()
+	state = applyResource(t, r, state, cleared, client)
+	sent = proxy.lastRequest("/team/update")
+	for _, attr := range []string{"model_aliases", "model_rpm_limit", "model_tpm_limit", "model_max_budget"} {
+		if v, ok := sent[attr].(map[string]interface{}); !ok || len(v) != 0 {
+			t.Errorf("expected %s to be cleared, got %#v", attr, sent[attr])
+		}
+	}
+	state = refreshResource(t, r, state, client)
+	assertNoDiff(t, r, state, cleared, client)
+}
@@ -35,12 +35,69 @@ type TeamResponse struct {
 	TeamAlias      string                 `json:"team_alias,omitempty"`
 	OrganizationID string                 `json:"organization_id,omitempty"`
 	Metadata       map[string]interface{} `json:"metadata,omitempty"`
-	TPMLimit       int                    `json:"tpm_limit,omitempty"`
-	RPMLimit       int                    `json:"rpm_limit,omitempty"`
-	MaxBudget      float64                `json:"max_budget,omitempty"`
+	TPMLimit       *int                   `json:"tpm_limit,omitempty"`
+	RPMLimit       *int                   `json:"rpm_limit,omitempty"`
+	MaxBudget      *float64               `json:"max_budget,omitempty"`
 	BudgetDuration string                 `json:"budget_duration,omitempty"`
 	Models         []string               `json:"models"`
 	Blocked        bool                   `json:"blocked,omitempty"`
+	BlockedReason  string                 `json:"-"`
+	ModelAliases   map[string]interface{} `json:"model_aliases,omitempty"`
+	ModelRPMLimit  map[string]interface{} `json:"model_rpm_limit,omitempty"`
+	ModelTPMLimit  map[string]interface{} `json:"model_tpm_limit,omitempty"`
+	ModelMaxBudget map[string]interface{} `json:"model_max_budget,omitempty"`
+
+	// The proxy stores model aliases in a separate model table
+	LiteLLMModelTable *struct {
+		ModelAliases map[string]interface{} `json:"model_aliases"`
+	} `json:"litellm_model_table,omitempty"`
+
+	// Defaults for new team members are stored in a budget table
+	TeamMemberBudgetTable *struct {
+		MaxBudget *float64 `json:"max_budget"`
+		RPMLimit  *int     `json:"rpm_limit"`
+		TPMLimit  *int     `json:"tpm_limit"`
+	} `json:"team_member_budget_table,omitempty"`
+	TeamMemberKeyDuration string `json:"-"`
+
+	// Routes non-admin team members are allowed to call
+	TeamMemberPermissions []string `json:"team_member_permissions"`
+
+	MembersWithRoles []TeamMember `json:"members_with_roles"`
+
+	// TeamMemberships holds the members' budgets within the team, which
+	// /team/info returns next to team_info
+	TeamMemberships []TeamMembership `json:"-"`
+
+	// RawMetadata is the metadata as stored by the proxy, including the
+	// fields it manages itself
+	RawMetadata map[string]interface{} `json:"-"`
+}
+
+// TeamInfoResponse represents the response of /team/info, which nests the team
+// under team_info.
+type TeamInfoResponse struct {
+	TeamID          string           `json:"team_id"`
+	TeamInfo        TeamResponse     `json:"team_info"`
+	TeamMemberships []TeamMembership `json:"team_memberships"`
+}
+
+// TeamMember represents a member of a team and their role in it.
+type TeamMember struct {
+	UserID    string `json:"user_id"`
+	UserEmail string `json:"user_email"`
+	Role      string `json:"role"`
+}
+
+// TeamMembership represents a member's budget within a team.
+type TeamMembership struct {
+	UserID             string `json:"user_id"`
+	TeamID             string `json:"team_id"`
+	LiteLLMBudgetTable *struct {
+		MaxBudget *float64 `json:"max_budget"`
+		TPMLimit  *int     `json:"tpm_limit"`
+		RPMLimit  *int     `json:"rpm_limit"`
+	} `json:"litellm_budget_table"`
 }
 
 // LiteLLMParams represents the parameters for LiteLLM.
@@ -69,30 +126,33 @@ type LiteLLMParams struct {
 	VertexCredentials              string                 `json:"vertex_credentials,omitempty"`
 }
 
-// ModelInfo represents information about a model.
+// ModelInfo represents information about a model. Metadata is sent as null
+// when unset, which the proxy ignores, and as an empty object to clear it.
 type ModelInfo struct {
-	ID        string `json:"id"`
-	DBModel   bool   `json:"db_model"`
-	BaseModel string `json:"base_model"`
-	Tier      string `json:"tier"`
-	Mode      string `json:"mode"`
+	ID        string                 `json:"id"`
+	DBModel   bool                   `json:"db_model"`
+	BaseModel string                 `json:"base_model"`
+	Tier      string                 `json:"tier"`
+	Mode      string                 `json:"mode"`
+	Metadata  map[string]interface{} `json:"metadata"`
 }
 
 // Key represents a LiteLLM API key.
 type Key struct {
 	Key                  string                 `json:"key,omitempty"`
+	Token                string                 `json:"token,omitempty"`
 	Models               []string               `json:"models"`
 	Spend                float64                `json:"spend,omitempty"`
-	MaxBudget            float64                `json:"max_budget,omitempty"`
+	MaxBudget            *float64               `json:"max_budget,omitempty"`
 	UserID               string                 `json:"user_id,omitempty"`
 	TeamID               string                 `json:"team_id,omitempty"`
-	MaxParallelRequests  int                    `json:"max_parallel_requests,omitempty"`
+	MaxParallelRequests  *int                   `json:"max_parallel_requests,omitempty"`
 	Metadata             map[string]interface{} `json:"metadata,omitempty"`
-	TPMLimit             int                    `json:"tpm_limit,omitempty"`
-	RPMLimit             int                    `json:"rpm_limit,omitempty"`
-	BudgetDuration       string                 `json:"budget_duration"`
+	TPMLimit             *int                   `json:"tpm_limit,omitempty"`
+	RPMLimit             *int                   `json:"rpm_limit,omitempty"`
+	BudgetDuration       string                 `json:"budget_duration,omitempty"`
 	AllowedCacheControls []string               `json:"allowed_cache_controls,omitempty"`
-	SoftBudget           float64                `json:"soft_budget,omitempty"`
+	SoftBudget           *float64               `json:"soft_budget,omitempty"`
 	KeyAlias             string                 `json:"key_alias,omitempty"`
 	Duration             string                 `json:"duration,omitempty"`
 	Aliases              map[string]interface{} `json:"aliases,omitempty"`
@@ -105,6 +165,27 @@ type Key struct {
 	Blocked              bool                   `json:"blocked"`
 	Tags                 []string               `json:"tags,omitempty"`
 	SendInviteEmail      bool                   `json:"send_invite_email,omitempty"`
+	AutoRotate           bool                   `json:"auto_rotate,omitempty"`
+	RotationInterval     string                 `json:"rotation_interval,omitempty"`
+	CreatedAt            string                 `json:"created_at,omitempty"`
+	LastRotationAt       string                 `json:"last_rotation_at,omitempty"`
+	Expires              string                 `json:"expires,omitempty"`
+	KeyType              string                 `json:"key_type,omitempty"`
+	AllowedRoutes        []string               `json:"allowed_routes,omitempty"`
+	EnforcedParams       []string               `json:"enforced_params,omitempty"`
+
+	// ServiceAccountID is stored in the key's metadata by the proxy
+	ServiceAccountID string `json:"-"`
+
+	// UpdateRotation makes UpdateKey send auto_rotate and rotation_interval,
+	// including when rotation is turned off
+	UpdateRotation bool `json:"-"`
+
+	// Temporary budget increases are only accepted by /key/update.
+	// ClearTempBudget makes UpdateKey remove one that still applies.
+	TempBudgetIncrease *float64 `json:"-"`
+	TempBudgetExpiry   string   `json:"-"`
+	ClearTempBudget    bool     `json:"-"`
 }
 
 // KeyResponse represents a response from the API containing key information.
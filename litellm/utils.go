@@ -3,10 +3,18 @@ package litellm
 import (
 	"bytes"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io/ioutil"
 	"net/http"
+	"regexp"
+	"strconv"
 	"strings"
+	"time"
+
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/structure"
 )
 
 func isModelNotFoundError(errResp ErrorResponse) bool {
@@ -27,6 +35,20 @@ func isModelNotFoundError(errResp ErrorResponse) bool {
 	return false
 }
 
+// isKeyNotFoundError reports whether err means the key no longer exists on the proxy.
+func isKeyNotFoundError(err error) bool {
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		return false
+	}
+
+	if apiErr.StatusCode == http.StatusNotFound {
+		return true
+	}
+
+	return strings.Contains(strings.ToLower(apiErr.Body), "key not found")
+}
+
 func handleAPIResponse(resp *http.Response, reqBody interface{}) (*ModelResponse, error) {
 	bodyBytes, err := ioutil.ReadAll(resp.Body)
 	if err != nil {
@@ -103,3 +125,156 @@ func GetFloatValue(apiValue, defaultValue float64) float64 {
 func GetBoolValue(apiValue, defaultValue bool) bool {
 	return apiValue
 }
+
+// isNullInConfig reports whether key is unset in the configuration, which
+// unlike d.GetOk tells an unset attribute apart from an explicit zero value.
+// Without a raw configuration, such as during import, the zero value is
+// treated as unset.
+func isNullInConfig(d *schema.ResourceData, key string) bool {
+	rawConfig := d.GetRawConfig()
+	if rawConfig.IsNull() || !rawConfig.IsKnown() {
+		_, ok := d.GetOk(key)
+		return !ok
+	}
+
+	return rawConfig.GetAttr(key).IsNull()
+}
+
+// getOptionalFloat returns nil if key is unset in the configuration.
+func getOptionalFloat(d *schema.ResourceData, key string) *float64 {
+	if isNullInConfig(d, key) {
+		return nil
+	}
+	v := d.Get(key).(float64)
+	return &v
+}
+
+// getOptionalInt returns nil if key is unset in the configuration.
+func getOptionalInt(d *schema.ResourceData, key string) *int {
+	if isNullInConfig(d, key) {
+		return nil
+	}
+	v := d.Get(key).(int)
+	return &v
+}
+
+// nullIfEmpty returns nil for an empty string, so that it is sent to the API as null.
+func nullIfEmpty(s string) interface{} {
+	if s == "" {
+		return nil
+	}
+	return s
+}
+
+// getWriteOnlyString returns the value of the write-only attribute "<key>_wo" from the
+// raw configuration. Write-only values are never persisted to state, so they have to be
+// read from the configuration on every create and update. Terraform versions before 1.11
+// don't support write-only attributes, in which case the regular attribute is used.
+func getWriteOnlyString(d *schema.ResourceData, key string) string {
+	if d.GetRawConfig().IsNull() {
+		return d.Get(key).(string)
+	}
+
+	v, diags := d.GetRawConfigAt(cty.GetAttrPath(key + "_wo"))
+	if diags.HasError() || !v.Type().Equals(cty.String) || !v.IsKnown() || v.IsNull() {
+		return d.Get(key).(string)
+	}
+
+	return v.AsString()
+}
+
+// liteLLMDurationPattern matches the duration syntax LiteLLM accepts for fields
+// such as duration and budget_duration, e.g. "30s", "15m", "12h", "30d", "2w" or "1mo".
+var liteLLMDurationPattern = regexp.MustCompile(`^(\d+)(s|m|h|d|w|mo)$`)
+
+// addLiteLLMDuration adds a LiteLLM duration string to t.
+func addLiteLLMDuration(t time.Time, duration string) (time.Time, error) {
+	match := liteLLMDurationPattern.FindStringSubmatch(duration)
+	if match == nil {
+		return t, fmt.Errorf("invalid duration %q: expected a number followed by s, m, h, d, w or mo", duration)
+	}
+
+	n, err := strconv.Atoi(match[1])
+	if err != nil {
+		return t, fmt.Errorf("invalid duration %q: %v", duration, err)
+	}
+
+	switch match[2] {
+	case "s":
+		return t.Add(time.Duration(n) * time.Second), nil
+	case "m":
+		return t.Add(time.Duration(n) * time.Minute), nil
+	case "h":
+		return t.Add(time.Duration(n) * time.Hour), nil
+	case "d":
+		return t.AddDate(0, 0, n), nil
+	case "w":
+		return t.AddDate(0, 0, 7*n), nil
+	default:
+		return t.AddDate(0, n, 0), nil
+	}
+}
+
+// parseTimestamp parses the timestamps returned by the LiteLLM API, which may
+// or may not include a timezone.
+func parseTimestamp(value string) (time.Time, error) {
+	for _, layout := range []string{time.RFC3339Nano, "2006-01-02T15:04:05.999999999", "2006-01-02 15:04:05.999999999Z07:00"} {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t.UTC(), nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid timestamp %q", value)
+}
+
+// validateJSONObject checks that a string attribute holds a JSON object.
+func validateJSONObject(v interface{}, k string) (warnings []string, errs []error) {
+	s, ok := v.(string)
+	if !ok {
+		return nil, []error{fmt.Errorf("expected type of %s to be string", k)}
+	}
+	if _, err := structure.ExpandJsonFromString(s); err != nil {
+		errs = append(errs, fmt.Errorf("%s must be a JSON object: %s", k, err))
+	}
+	return warnings, errs
+}
+
+// getMetadata returns the metadata to send to the API, taken from metadata_json
+// when it is set and from the flat metadata map otherwise.
+func getMetadata(d *schema.ResourceData) map[string]interface{} {
+	if v := d.Get("metadata_json").(string); v != "" {
+		// metadata_json is validated as a JSON object at plan time
+		metadata, _ := structure.ExpandJsonFromString(v)
+		return metadata
+	}
+	return d.Get("metadata").(map[string]interface{})
+}
+
+// setMetadata stores metadata in whichever of metadata_json and metadata the
+// resource uses. The flat map can only hold strings, so other values are
+// stored JSON-encoded.
+func setMetadata(d *schema.ResourceData, metadata map[string]interface{}) error {
+	if d.Get("metadata_json").(string) != "" {
+		if metadata == nil {
+			metadata = map[string]interface{}{}
+		}
+		v, err := structure.FlattenJsonToString(metadata)
+		if err != nil {
+			return fmt.Errorf("error encoding metadata: %s", err)
+		}
+		return d.Set("metadata_json", v)
+	}
+
+	flat := make(map[string]string, len(metadata))
+	for k, v := range metadata {
+		if s, ok := v.(string); ok {
+			flat[k] = s
+			continue
+		}
+		b, err := json.Marshal(v)
+		if err != nil {
+			return fmt.Errorf("error encoding metadata %q: %s", k, err)
+		}
+		flat[k] = string(b)
+	}
+	return d.Set("metadata", flat)
+}
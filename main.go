@@ -1,14 +1,33 @@
 package main
 
 import (
-	"github.com/hashicorp/terraform-plugin-sdk/v2/plugin"
+	"context"
+	"log"
+
+	"github.com/hashicorp/terraform-plugin-framework/providerserver"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5/tf5server"
+	"github.com/hashicorp/terraform-plugin-mux/tf5muxserver"
 	"github.com/vbanthia/terraform-provider-litellm/litellm"
 )
 
-// main is the entry point for the plugin. It serves the provider
-// using the Terraform plugin SDK.
+// main is the entry point for the plugin. It serves the SDK provider muxed
+// with the plugin framework provider, which hosts the ephemeral resources.
 func main() {
-	plugin.Serve(&plugin.ServeOpts{
-		ProviderFunc: litellm.Provider,
-	})
+	ctx := context.Background()
+
+	providers := []func() tfprotov5.ProviderServer{
+		litellm.Provider().GRPCProvider,
+		providerserver.NewProtocol5(litellm.NewFrameworkProvider()),
+	}
+
+	muxServer, err := tf5muxserver.NewMuxServer(ctx, providers...)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	err = tf5server.Serve("registry.terraform.io/ncecere/litellm", muxServer.ProviderServer)
+	if err != nil {
+		log.Fatal(err)
+	}
 }